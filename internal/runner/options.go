@@ -33,6 +33,7 @@ type Options struct {
 	Id                 goflags.StringSlice // Id specifies what id's to fetch assets for.
 	Services           goflags.StringSlice // Services specifies what services to fetch assets for a provider.
 	ProviderConfig     string              // ProviderConfig is the location of the provider config file.
+	SinkConfig         string              // SinkConfig is the location of the sink config file.
 	DisableUpdateCheck bool                // DisableUpdateCheck disable automatic update check
 }
 
@@ -76,6 +77,7 @@ func ParseOptions() *Options {
 	flagSet.CreateGroup("config", "Configuration",
 		flagSet.StringVar(&options.Config, "config", defaultConfigLocation, "cloudlist flag config file"),
 		flagSet.StringVarP(&options.ProviderConfig, "provider-config", "pc", defaultProviderConfigLocation, "provider config file"),
+		flagSet.StringVarP(&options.SinkConfig, "sink-config", "sc", "", "sink config file to send results to webhooks, s3 or elasticsearch"),
 	)
 	flagSet.CreateGroup("filter", "Filters",
 		flagSet.StringSliceVarP(&options.Providers, "provider", "p", nil, "display results for given providers (comma-separated) (default "+strings.Join(defaultProviders, ",")+")", goflags.CommaSeparatedStringSliceOptions),
@@ -204,12 +206,19 @@ const defaultProviderConfigFile = `#  #Provider configuration file for cloudlist
 #  aws_secret_key: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
 #  # aws_session_token session token for temporary security credentials retrieved via STS (optional)
 #  aws_session_token: xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx
+#  # aws_profile is the shared config profile used when no static keys are set (optional)
+#  aws_profile: default
 #  # assume_role_name is the name of the role to assume (optional)
 #  assume_role_name: xxxxxxxx
 #  # account_ids is the aws account ids which has similar assumed role name (optional)
 #  account_ids:
 #    - xxxxxxxxxxx
 #    - xxxxxxxxxxx
+#  # accounts overrides the role to assume per account (optional)
+#  accounts:
+#    - account_id: xxxxxxxxxxx
+#      role_name: xxxxxxxx
+#      external_id: xxxxxxxx
 #
 #- # provider is the name of the provider
 #  provider: gcp
@@ -10,6 +10,7 @@ import (
 	jsoniter "github.com/json-iterator/go"
 	"github.com/projectdiscovery/cloudlist/pkg/inventory"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/cloudlist/pkg/sink"
 	"github.com/projectdiscovery/gologger"
 )
 
@@ -17,6 +18,7 @@ import (
 type Runner struct {
 	config  schema.Options
 	options *Options
+	sinks   sink.Sinks
 }
 
 // New creates a new runner instance based on configuration options
@@ -45,7 +47,18 @@ func New(options *Options) (*Runner, error) {
 		options.Providers = append(options.Providers, defaultProviders...)
 	}
 
-	return &Runner{config: config, options: options}, nil
+	runner := &Runner{config: config, options: options}
+	if options.SinkConfig != "" {
+		sinkConfig, err := inventory.ParseOptions(options.SinkConfig)
+		if err != nil {
+			return nil, err
+		}
+		runner.sinks, err = sink.New(sinkConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return runner, nil
 }
 
 // Enumerate performs the cloudlist enumeration process
@@ -96,6 +109,7 @@ func (r *Runner) Enumerate() {
 
 	builder := &bytes.Buffer{}
 	deduplicator := schema.NewResourceDeduplicator()
+	defer r.sinks.Close(context.Background())
 	for _, provider := range inventory.Providers {
 		gologger.Info().Msgf("Listing assets from provider: %s services: %s id: %s", provider.Name(), strings.Join(provider.Services(), ","), provider.ID())
 
@@ -105,11 +119,13 @@ func (r *Runner) Enumerate() {
 			continue
 		}
 		var hostsCount, ipCount int
+		var processed []*schema.Resource
 		for _, instance := range instances.Items {
 			// Skip if already processed
 			if !deduplicator.ProcessResource(instance) {
 				continue
 			}
+			processed = append(processed, instance)
 
 			builder.Reset()
 
@@ -231,6 +247,8 @@ func (r *Runner) Enumerate() {
 				gologger.Silent().Msgf("%s", instance.PrivateIpv6)
 			}
 		}
+		r.sinks.Write(context.Background(), processed)
+
 		logBuilder := &strings.Builder{}
 		if hostsCount != 0 {
 			logBuilder.WriteString(strconv.Itoa(hostsCount))
@@ -0,0 +1,200 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+// AccountConfig is the role configuration of an account enumerated
+// in addition to the one of the base credentials.
+//
+// Empty fields fall back to the top level assume_role_name, external_id,
+// assume_role_session_name and assume_role_duration options.
+type AccountConfig struct {
+	AccountID string `json:"account_id"`
+	// RoleArn is the role to assume in the account. A list of arns
+	// is assumed in order, each hop using the credentials of the previous one.
+	RoleArn     roleChain `json:"role_arn"`
+	RoleName    string    `json:"role_name"`
+	ExternalID  string    `json:"external_id"`
+	SessionName string    `json:"session_name"`
+	Duration    string    `json:"duration"`
+}
+
+// roleChain is a list of role arns accepting either a string or a list
+type roleChain []string
+
+func (r *roleChain) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*r = splitList(value)
+		return nil
+	}
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*r = values
+	return nil
+}
+
+// parseAccounts parses the accounts option block value
+func parseAccounts(value string) ([]AccountConfig, error) {
+	var accounts []AccountConfig
+	if err := json.Unmarshal([]byte(value), &accounts); err != nil {
+		return nil, errors.Wrap(err, "could not parse accounts")
+	}
+	for i, account := range accounts {
+		if account.AccountID == "" && len(account.RoleArn) == 0 {
+			return nil, fmt.Errorf("account %d has neither account_id nor role_arn", i)
+		}
+		if account.Duration != "" {
+			if _, err := time.ParseDuration(account.Duration); err != nil {
+				return nil, errors.Wrapf(err, "invalid duration for account %s", account.AccountID)
+			}
+		}
+	}
+	return accounts, nil
+}
+
+// roleHop is a single role assumption of a chain
+type roleHop struct {
+	arn         string
+	externalID  string
+	sessionName string
+	duration    time.Duration
+}
+
+// assumeRoleChain returns a config assuming every hop in order starting
+// from the given config. Credentials are cached and refreshed automatically.
+func assumeRoleChain(cfg aws.Config, hops []roleHop) aws.Config {
+	for _, hop := range hops {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), hop.arn, func(o *stscreds.AssumeRoleOptions) {
+			if hop.sessionName != "" {
+				o.RoleSessionName = hop.sessionName
+			}
+			if hop.externalID != "" {
+				o.ExternalID = aws.String(hop.externalID)
+			}
+			if hop.duration > 0 {
+				o.Duration = hop.duration
+			}
+		})
+		cfg = cfg.Copy()
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg
+}
+
+// accountConfigs returns the configs of every account to enumerate.
+//
+// The first config is the one of the base credentials, followed by one
+// config per account from account_ids and accounts. Accounts whose role
+// cannot be assumed are reported and skipped.
+func accountConfigs(ctx context.Context, cfg aws.Config, options *ProviderOptions) []aws.Config {
+	configs := []aws.Config{cfg}
+
+	seen := make(map[string]struct{})
+	var chains [][]roleHop
+	for _, account := range options.Accounts {
+		if account.AccountID != "" {
+			seen[account.AccountID] = struct{}{}
+		}
+		chains = append(chains, options.accountChain(account))
+	}
+	if options.AssumeRoleName != "" {
+		for _, accountID := range options.AccountIds {
+			if _, ok := seen[accountID]; ok {
+				continue
+			}
+			chains = append(chains, options.accountChain(AccountConfig{AccountID: accountID}))
+		}
+	}
+
+	for _, chain := range chains {
+		if len(chain) == 0 {
+			continue
+		}
+		target := chain[len(chain)-1].arn
+		accountConfig := assumeRoleChain(cfg, chain)
+		if _, err := accountConfig.Credentials.Retrieve(ctx); err != nil {
+			gologger.Warning().Msgf("Could not assume role %s: %s", target, err)
+			continue
+		}
+		gologger.Verbose().Msgf("Assumed role %s", target)
+		configs = append(configs, accountConfig)
+	}
+	return configs
+}
+
+// accountChain returns the role hops to reach the account, applying
+// the top level defaults to the fields not set for the account.
+func (p *ProviderOptions) accountChain(account AccountConfig) []roleHop {
+	hop := roleHop{
+		externalID:  account.ExternalID,
+		sessionName: account.SessionName,
+		duration:    p.AssumeRoleDuration,
+	}
+	if hop.externalID == "" {
+		hop.externalID = p.ExternalId
+	}
+	if hop.sessionName == "" {
+		hop.sessionName = p.AssumeRoleSessionName
+	}
+	if account.Duration != "" {
+		hop.duration, _ = time.ParseDuration(account.Duration)
+	}
+
+	arns := []string(account.RoleArn)
+	if len(arns) == 0 {
+		roleName := account.RoleName
+		if roleName == "" {
+			roleName = p.AssumeRoleName
+		}
+		if roleName == "" {
+			gologger.Warning().Msgf("No role configured for account %s, skipping", account.AccountID)
+			return nil
+		}
+		arns = []string{fmt.Sprintf("arn:aws:iam::%s:role/%s", account.AccountID, roleName)}
+	}
+
+	hops := make([]roleHop, 0, len(arns))
+	for _, arn := range arns {
+		hop.arn = arn
+		hops = append(hops, hop)
+	}
+	return hops
+}
+
+// rootChain returns the role hops configured with assume_role_arn
+func (p *ProviderOptions) rootChain() []roleHop {
+	var hops []roleHop
+	for _, arn := range splitList(p.AssumeRoleArn) {
+		hops = append(hops, roleHop{
+			arn:         arn,
+			externalID:  p.ExternalId,
+			sessionName: p.AssumeRoleSessionName,
+			duration:    p.AssumeRoleDuration,
+		})
+	}
+	return hops
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
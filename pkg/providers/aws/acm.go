@@ -0,0 +1,181 @@
+package aws
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/acm"
+	acmtypes "github.com/aws/aws-sdk-go-v2/service/acm/types"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// acmProvider is a provider for the subject alternative names of
+// AWS Certificate Manager and IAM server certificates
+type acmProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ap *acmProvider) name() string {
+	return "acm"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ap *acmProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := listAllRegions(ctx, ap.configs, ap.regions, ap.listCertificates)
+
+	// iam server certificates are global
+	for _, iamClient := range newClients(ap.configs, globalRegion, iam.NewFromConfig) {
+		if resources, err := ap.listServerCertificates(ctx, iamClient); err == nil {
+			list.Merge(resources)
+		}
+	}
+	return list, nil
+}
+
+func (ap *acmProvider) listCertificates(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	client := newClient(cfg, region, acm.NewFromConfig)
+
+	// only rsa 1024 and 2048 certificates are listed by default
+	req := &acm.ListCertificatesInput{Includes: &acmtypes.Filters{KeyTypes: acmtypes.KeyAlgorithm("").Values()}}
+	var arns []*string
+	paginator := acm.NewListCertificatesPaginator(client, req)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range page.CertificateSummaryList {
+			arns = append(arns, summary.CertificateArn)
+		}
+	}
+
+	list := schema.NewResources()
+	for _, arn := range arns {
+		output, err := client.DescribeCertificate(ctx, &acm.DescribeCertificateInput{CertificateArn: arn})
+		if err != nil || output.Certificate == nil {
+			continue
+		}
+		certificate := output.Certificate
+		metadata := map[string]string{
+			"region":           region,
+			"certificate_arn":  aws.ToString(certificate.CertificateArn),
+			"certificate_type": string(certificate.Type),
+			"status":           string(certificate.Status),
+			"in_use_by":        strings.Join(certificate.InUseBy, ","),
+		}
+		if certificate.NotAfter != nil {
+			metadata["expiry"] = certificate.NotAfter.UTC().Format(time.RFC3339)
+		}
+
+		names := certificate.SubjectAlternativeNames
+		if len(names) == 0 {
+			names = []string{aws.ToString(certificate.DomainName)}
+		}
+		list.Merge(ap.certificateResources(names, metadata))
+	}
+	return list, nil
+}
+
+func (ap *acmProvider) listServerCertificates(ctx context.Context, iamClient *iam.Client) (*schema.Resources, error) {
+	var certificates []iamtypes.ServerCertificateMetadata
+	paginator := iam.NewListServerCertificatesPaginator(iamClient, &iam.ListServerCertificatesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, page.ServerCertificateMetadataList...)
+	}
+
+	list := schema.NewResources()
+	for _, certificate := range certificates {
+		// the names are only available from the certificate body
+		output, err := iamClient.GetServerCertificate(ctx, &iam.GetServerCertificateInput{ServerCertificateName: certificate.ServerCertificateName})
+		if err != nil || output.ServerCertificate == nil {
+			continue
+		}
+		block, _ := pem.Decode([]byte(aws.ToString(output.ServerCertificate.CertificateBody)))
+		if block == nil {
+			continue
+		}
+		parsed, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		metadata := map[string]string{
+			"certificate_arn":  aws.ToString(certificate.Arn),
+			"certificate_name": aws.ToString(certificate.ServerCertificateName),
+			"certificate_type": "IAM",
+		}
+		if certificate.Expiration != nil {
+			metadata["expiry"] = certificate.Expiration.UTC().Format(time.RFC3339)
+		}
+
+		names := parsed.DNSNames
+		if len(names) == 0 && parsed.Subject.CommonName != "" {
+			names = []string{parsed.Subject.CommonName}
+		}
+		list.Merge(ap.certificateResources(names, metadata))
+	}
+	return list, nil
+}
+
+// certificateResources returns a resource for every name of a certificate.
+// Wildcard names are not valid hosts, they are reported with their base
+// domain and the wildcard metadata, and are also listed in the wildcard_sans
+// metadata of the other names.
+func (ap *acmProvider) certificateResources(names []string, certificateMetadata map[string]string) *schema.Resources {
+	list := schema.NewResources()
+
+	var hosts, wildcards []string
+	for _, name := range names {
+		if strings.HasPrefix(name, "*.") {
+			wildcards = append(wildcards, name)
+		} else {
+			hosts = append(hosts, name)
+		}
+	}
+	withMetadata := func(extra map[string]string) map[string]string {
+		metadata := make(map[string]string, len(certificateMetadata)+len(extra))
+		for key, value := range certificateMetadata {
+			metadata[key] = value
+		}
+		for key, value := range extra {
+			metadata[key] = value
+		}
+		return cleanMetadata(metadata)
+	}
+
+	// the names are appended first so that they are kept over a wildcard
+	// having them as base domain
+	hostMetadata := withMetadata(map[string]string{"wildcard_sans": strings.Join(wildcards, ",")})
+	for _, host := range hosts {
+		list.Append(&schema.Resource{
+			ID:       ap.options.Id,
+			Provider: providerName,
+			DNSName:  host,
+			Public:   true,
+			Service:  ap.name(),
+			Metadata: hostMetadata,
+		})
+	}
+	for _, wildcard := range wildcards {
+		list.Append(&schema.Resource{
+			ID:       ap.options.Id,
+			Provider: providerName,
+			DNSName:  strings.TrimPrefix(wildcard, "*."),
+			Public:   true,
+			Service:  ap.name(),
+			Metadata: withMetadata(map[string]string{"wildcard": "true", "wildcard_san": wildcard}),
+		})
+	}
+	return list
+}
@@ -2,24 +2,20 @@ package aws
 
 import (
 	"context"
-	"fmt"
-	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 )
 
 // elbV2Provider is a provider for AWS Application Load Balancing (ELBV2) resources
 type elbV2Provider struct {
-	options   ProviderOptions
-	albClient *elbv2.ELBV2
-	session   *session.Session
-	regions   *ec2.DescribeRegionsOutput
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
 }
 
 func (ep *elbV2Provider) name() string {
@@ -28,143 +24,85 @@ func (ep *elbV2Provider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (ep *elbV2Provider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, region := range ep.regions.Regions {
-		albClients, ec2Clients := ep.getElbV2AndEc2Clients(region.RegionName)
-		for index := range len(albClients) {
-			wg.Add(1)
-
-			go func(albClient *elbv2.ELBV2, ec2Client *ec2.EC2) {
-				defer wg.Done()
-
-				if resources, err := ep.listELBV2Resources(albClient, ec2Client); err == nil {
-					mu.Lock()
-					list.Merge(resources)
-					mu.Unlock()
-				}
-			}(albClients[index], ec2Clients[index])
-		}
-	}
-	wg.Wait()
-	return list, nil
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listELBV2Resources), nil
 }
 
-func (ep *elbV2Provider) listELBV2Resources(albClient *elbv2.ELBV2, ec2Client *ec2.EC2) (*schema.Resources, error) {
+func (ep *elbV2Provider) listELBV2Resources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
 	list := schema.NewResources()
-
-	loadBalancers, err := ep.getLoadBalancers(albClient)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, lb := range loadBalancers {
-		albDNS := *lb.DNSName
-		resource := &schema.Resource{
-			Provider: "aws",
-			ID:       *lb.LoadBalancerName,
-			DNSName:  albDNS,
-			Public:   true,
-			Service:  ep.name(),
-		}
-		list.Append(resource)
-
-		if ec2Client == nil {
-			continue
-		}
-		// Describe targets for the Load Balancer
-		targetsOutput, err := albClient.DescribeTargetGroups(&elbv2.DescribeTargetGroupsInput{
-			LoadBalancerArn: lb.LoadBalancerArn,
-		})
+	albClient := newClient(cfg, region, elasticloadbalancingv2.NewFromConfig)
+	ec2Client := newClient(cfg, region, ec2.NewFromConfig)
+
+	paginator := elasticloadbalancingv2.NewDescribeLoadBalancersPaginator(albClient, &elasticloadbalancingv2.DescribeLoadBalancersInput{
+		PageSize: aws.Int32(400),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			continue
+			return nil, err
 		}
 
-		for _, tg := range targetsOutput.TargetGroups {
-			targets, err := albClient.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
-				TargetGroupArn: tg.TargetGroupArn,
+		for _, lb := range page.LoadBalancers {
+			list.Append(&schema.Resource{
+				Provider: "aws",
+				ID:       aws.ToString(lb.LoadBalancerName),
+				DNSName:  aws.ToString(lb.DNSName),
+				Public:   true,
+				Service:  ep.name(),
 			})
-			if err != nil {
+
+			instanceIDs, err := ep.targetInstances(ctx, albClient, lb.LoadBalancerArn)
+			if err != nil || len(instanceIDs) == 0 {
 				continue
 			}
-
-			for _, target := range targets.TargetHealthDescriptions {
-				instanceID := *target.Target.Id
-				instanceOutput, err := ec2Client.DescribeInstances(&ec2.DescribeInstancesInput{
-					InstanceIds: []*string{&instanceID},
-				})
-				if err != nil {
-					return nil, errors.Wrapf(err, "could not describe instance %s", instanceID)
-				}
-				// Extract private IP address
-				for _, reservation := range instanceOutput.Reservations {
-					for _, instance := range reservation.Instances {
-						if instance.PrivateIpAddress != nil {
-							resource := &schema.Resource{
-								Provider:    "aws",
-								ID:          instanceID,
-								PrivateIpv4: *instance.PrivateIpAddress,
-								Public:      false,
-								Service:     ep.name(),
-							}
-							list.Append(resource)
-						}
-					}
+			instances, err := describeInstances(ctx, ec2Client, instanceIDs)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not describe target instances")
+			}
+			for id, instance := range instances {
+				if instance.PrivateIpAddress == nil {
+					continue
 				}
+				list.Append(&schema.Resource{
+					Provider:    "aws",
+					ID:          id,
+					PrivateIpv4: aws.ToString(instance.PrivateIpAddress),
+					Public:      false,
+					Service:     ep.name(),
+				})
 			}
 		}
 	}
 	return list, nil
 }
 
-func (ep *elbV2Provider) getLoadBalancers(albClient *elbv2.ELBV2) ([]*elbv2.LoadBalancer, error) {
-	var loadBalancers []*elbv2.LoadBalancer
-	req := &elbv2.DescribeLoadBalancersInput{
-		PageSize: aws.Int64(20),
-	}
-	for {
-		lbOutput, err := albClient.DescribeLoadBalancers(req)
+// targetInstances returns the ids of the instances registered as targets of a load balancer
+func (ep *elbV2Provider) targetInstances(ctx context.Context, albClient *elasticloadbalancingv2.Client, loadBalancerArn *string) ([]string, error) {
+	var instanceIDs []string
+	paginator := elasticloadbalancingv2.NewDescribeTargetGroupsPaginator(albClient, &elasticloadbalancingv2.DescribeTargetGroupsInput{
+		LoadBalancerArn: loadBalancerArn,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
-		loadBalancers = append(loadBalancers, lbOutput.LoadBalancers...)
-		if aws.StringValue(req.Marker) == "" {
-			break
-		}
-		req.SetMarker(aws.StringValue(req.Marker))
-	}
-	return loadBalancers, nil
-}
-
-func (ep *elbV2Provider) getElbV2AndEc2Clients(region *string) ([]*elbv2.ELBV2, []*ec2.EC2) {
-	albClients := make([]*elbv2.ELBV2, 0)
-	ec2Clients := make([]*ec2.EC2, 0)
-
-	albClient := elbv2.New(ep.session, aws.NewConfig().WithRegion(*region))
-	albClients = append(albClients, albClient)
-
-	ec2Client := ec2.New(ep.session, aws.NewConfig().WithRegion(*region))
-	ec2Clients = append(ec2Clients, ec2Client)
-
-	if ep.options.AssumeRoleName == "" || len(ep.options.AccountIds) < 1 {
-		return albClients, ec2Clients
-	}
-
-	for _, accountId := range ep.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, ep.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(ep.session, roleARN)
-
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      region,
-			Credentials: creds,
-		})
-		if err != nil {
-			continue
+		for _, tg := range page.TargetGroups {
+			// ip and lambda targets are not instances
+			if tg.TargetType != "" && tg.TargetType != "instance" {
+				continue
+			}
+			targets, err := albClient.DescribeTargetHealth(ctx, &elasticloadbalancingv2.DescribeTargetHealthInput{
+				TargetGroupArn: tg.TargetGroupArn,
+			})
+			if err != nil {
+				continue
+			}
+			for _, target := range targets.TargetHealthDescriptions {
+				if target.Target != nil {
+					instanceIDs = append(instanceIDs, aws.ToString(target.Target.Id))
+				}
+			}
 		}
-		albClients = append(albClients, elbv2.New(assumeSession))
-		ec2Clients = append(ec2Clients, ec2.New(assumeSession))
 	}
-	return albClients, ec2Clients
+	return sliceutil.Dedupe(instanceIDs), nil
 }
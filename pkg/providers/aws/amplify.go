@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/amplify"
+	"github.com/aws/aws-sdk-go-v2/service/amplify/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// amplifyProvider is a provider for AWS Amplify Hosting branches and custom domains
+type amplifyProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ap *amplifyProvider) name() string {
+	return "amplify"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ap *amplifyProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ap.configs, ap.regions, ap.listApps), nil
+}
+
+func (ap *amplifyProvider) listApps(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	client := newClient(cfg, region, amplify.NewFromConfig)
+
+	paginator := amplify.NewListAppsPaginator(client, &amplify.ListAppsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, app := range page.Apps {
+			list.Merge(ap.listAppDomains(ctx, client, &app, region))
+		}
+	}
+	return list, nil
+}
+
+// listAppDomains lists the <branch>.<default domain> hostname of every
+// branch of an app along with the subdomains of its custom domains.
+func (ap *amplifyProvider) listAppDomains(ctx context.Context, client *amplify.Client, app *types.App, region string) *schema.Resources {
+	list := schema.NewResources()
+	appendHost := func(host, branch string, extra map[string]string) {
+		metadata := map[string]string{
+			"region":   region,
+			"app_id":   aws.ToString(app.AppId),
+			"app_name": aws.ToString(app.Name),
+			"branch":   branch,
+		}
+		for key, value := range extra {
+			metadata[key] = value
+		}
+		list.Append(&schema.Resource{
+			ID:       ap.options.Id,
+			Provider: providerName,
+			DNSName:  host,
+			Public:   true,
+			Service:  ap.name(),
+			Metadata: cleanMetadata(metadata),
+		})
+	}
+
+	defaultDomain := aws.ToString(app.DefaultDomain)
+	branches := amplify.NewListBranchesPaginator(client, &amplify.ListBranchesInput{AppId: app.AppId})
+	for branches.HasMorePages() {
+		resp, err := branches.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, branch := range resp.Branches {
+			appendHost(aws.ToString(branch.DisplayName)+"."+defaultDomain, aws.ToString(branch.BranchName), map[string]string{
+				"stage": string(branch.Stage),
+			})
+		}
+	}
+
+	domains := amplify.NewListDomainAssociationsPaginator(client, &amplify.ListDomainAssociationsInput{AppId: app.AppId})
+	for domains.HasMorePages() {
+		resp, err := domains.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, association := range resp.DomainAssociations {
+			domain := aws.ToString(association.DomainName)
+			for _, subDomain := range association.SubDomains {
+				if subDomain.SubDomainSetting == nil {
+					continue
+				}
+				host := domain
+				if prefix := aws.ToString(subDomain.SubDomainSetting.Prefix); prefix != "" {
+					host = prefix + "." + domain
+				}
+				appendHost(host, aws.ToString(subDomain.SubDomainSetting.BranchName), map[string]string{
+					"custom_domain": domain,
+					"domain_status": string(association.DomainStatus),
+					"dns_record":    aws.ToString(subDomain.DnsRecord),
+				})
+			}
+		}
+	}
+	return list
+}
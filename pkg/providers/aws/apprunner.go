@@ -0,0 +1,81 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apprunner"
+	"github.com/aws/aws-sdk-go-v2/service/apprunner/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// appRunnerProvider is a provider for AWS App Runner services and their custom domains
+type appRunnerProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ap *appRunnerProvider) name() string {
+	return "apprunner"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ap *appRunnerProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ap.configs, ap.regions, ap.listServices), nil
+}
+
+func (ap *appRunnerProvider) listServices(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	client := newClient(cfg, region, apprunner.NewFromConfig)
+
+	var services []types.ServiceSummary
+	paginator := apprunner.NewListServicesPaginator(client, &apprunner.ListServicesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, page.ServiceSummaryList...)
+	}
+
+	list := schema.NewResources()
+	for _, service := range services {
+		// services with private ingress are only reachable through a vpc endpoint
+		public := true
+		if described, err := client.DescribeService(ctx, &apprunner.DescribeServiceInput{ServiceArn: service.ServiceArn}); err == nil {
+			if network := described.Service.NetworkConfiguration; network != nil && network.IngressConfiguration != nil {
+				public = network.IngressConfiguration.IsPubliclyAccessible
+			}
+		}
+		metadata := map[string]string{
+			"region":       region,
+			"service_name": aws.ToString(service.ServiceName),
+			"service_arn":  aws.ToString(service.ServiceArn),
+			"status":       string(service.Status),
+		}
+		list.Append(endpointResource(ap.options.Id, ap.name(), aws.ToString(service.ServiceUrl), 443, public, metadata))
+
+		domains := apprunner.NewDescribeCustomDomainsPaginator(client, &apprunner.DescribeCustomDomainsInput{ServiceArn: service.ServiceArn})
+		for domains.HasMorePages() {
+			page, err := domains.NextPage(ctx)
+			if err != nil {
+				break
+			}
+			for _, domain := range page.CustomDomains {
+				domainMetadata := map[string]string{
+					"region":        region,
+					"service_name":  aws.ToString(service.ServiceName),
+					"service_arn":   aws.ToString(service.ServiceArn),
+					"status":        string(domain.Status),
+					"target":        aws.ToString(page.DNSTarget),
+					"custom_domain": aws.ToString(domain.DomainName),
+				}
+				list.Append(endpointResource(ap.options.Id, ap.name(), aws.ToString(domain.DomainName), 443, public, domainMetadata))
+				if aws.ToBool(domain.EnableWWWSubdomain) {
+					list.Append(endpointResource(ap.options.Id, ap.name(), "www."+aws.ToString(domain.DomainName), 443, public, domainMetadata))
+				}
+			}
+		}
+	}
+	return list, nil
+}
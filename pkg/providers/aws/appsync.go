@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"context"
+	"net/url"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/appsync"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// appsyncProvider is a provider for AWS AppSync GraphQL APIs and custom domains
+type appsyncProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ap *appsyncProvider) name() string {
+	return "appsync"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ap *appsyncProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ap.configs, ap.regions, ap.listAppSyncResources), nil
+}
+
+func (ap *appsyncProvider) listAppSyncResources(ctx context.Context, cfg aws.Config, regionName string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	appsyncClient := newClient(cfg, regionName, appsync.NewFromConfig)
+
+	paginator := appsync.NewListGraphqlApisPaginator(appsyncClient, &appsync.ListGraphqlApisInput{MaxResults: 25})
+	for paginator.HasMorePages() {
+		apis, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list GraphQL APIs")
+		}
+		for _, api := range apis.GraphqlApis {
+			// uris contain the GRAPHQL and REALTIME endpoints of the api
+			kinds := make([]string, 0, len(api.Uris))
+			for kind := range api.Uris {
+				kinds = append(kinds, kind)
+			}
+			sort.Strings(kinds)
+
+			for _, kind := range kinds {
+				endpoint := api.Uris[kind]
+				parsed, err := url.Parse(endpoint)
+				if err != nil || parsed.Hostname() == "" {
+					continue
+				}
+				list.Append(&schema.Resource{
+					ID:       ap.options.Id,
+					Provider: providerName,
+					DNSName:  parsed.Hostname(),
+					Public:   true,
+					Service:  ap.name(),
+					Metadata: cleanMetadata(map[string]string{
+						"region":              regionName,
+						"api_id":              aws.ToString(api.ApiId),
+						"api_name":            aws.ToString(api.Name),
+						"endpoint_type":       kind,
+						"authentication_type": string(api.AuthenticationType),
+						"visibility":          string(api.Visibility),
+						"url":                 endpoint,
+					}),
+				})
+			}
+		}
+	}
+
+	domainPaginator := appsync.NewListDomainNamesPaginator(appsyncClient, &appsync.ListDomainNamesInput{MaxResults: 25})
+	for domainPaginator.HasMorePages() {
+		domains, err := domainPaginator.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, domain := range domains.DomainNameConfigs {
+			metadata := map[string]string{
+				"region":          regionName,
+				"custom_domain":   aws.ToString(domain.DomainName),
+				"target":          aws.ToString(domain.AppsyncDomainName),
+				"certificate_arn": aws.ToString(domain.CertificateArn),
+				"url":             "https://" + aws.ToString(domain.DomainName) + "/graphql",
+			}
+			if association, err := appsyncClient.GetApiAssociation(ctx, &appsync.GetApiAssociationInput{DomainName: domain.DomainName}); err == nil && association.ApiAssociation != nil {
+				metadata["api_id"] = aws.ToString(association.ApiAssociation.ApiId)
+			}
+			metadata = cleanMetadata(metadata)
+			for _, host := range []string{aws.ToString(domain.DomainName), aws.ToString(domain.AppsyncDomainName)} {
+				if host == "" {
+					continue
+				}
+				list.Append(&schema.Resource{
+					ID:       ap.options.Id,
+					Provider: providerName,
+					DNSName:  host,
+					Public:   true,
+					Service:  ap.name(),
+					Metadata: metadata,
+				})
+			}
+		}
+	}
+	return list, nil
+}
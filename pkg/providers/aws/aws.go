@@ -2,57 +2,63 @@ package aws
 
 import (
 	"context"
+	"strconv"
 	"strings"
 	"sync"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/apigateway"
-	"github.com/aws/aws-sdk-go/service/cloudfront"
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/ecs"
-	"github.com/aws/aws-sdk-go/service/eks"
-	"github.com/aws/aws-sdk-go/service/elb"
-	"github.com/aws/aws-sdk-go/service/elbv2"
-	"github.com/aws/aws-sdk-go/service/lambda"
-	"github.com/aws/aws-sdk-go/service/lightsail"
-	"github.com/aws/aws-sdk-go/service/route53"
-	"github.com/aws/aws-sdk-go/service/s3"
-	"github.com/aws/aws-sdk-go/service/sts"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	"github.com/aws/aws-sdk-go-v2/service/elasticloadbalancingv2"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lightsail"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	sliceutil "github.com/projectdiscovery/utils/slice"
 )
 
-var Services = []string{"ec2", "instance", "route53", "s3", "ecs", "eks", "lambda", "apigateway", "alb", "elb", "lightsail", "cloudfront"}
+var Services = []string{"ec2", "instance", "route53", "s3", "ecs", "eks", "lambda", "apigateway", "alb", "elb", "lightsail", "cloudfront", "eip", "eni", "appsync", "rds", "redshift", "opensearch", "elasticache", "docdb", "elasticbeanstalk", "apprunner", "amplify", "acm"}
 
 type ProviderOptions struct {
 	Id                    string
 	AccessKey             string
 	SecretKey             string
 	Token                 string
+	Profile               string
 	AssumeRoleArn         string
 	AssumeRoleSessionName string
 	ExternalId            string
 	AssumeRoleName        string
+	AssumeRoleDuration    time.Duration
 	AccountIds            []string
+	Accounts              []AccountConfig
 	Services              schema.ServiceMap
+	// ConfigAggregator is the aws config aggregator the resources are listed from
+	ConfigAggregator       string
+	ConfigAggregatorRegion string
 }
 
 func (p *ProviderOptions) ParseOptionBlock(block schema.OptionBlock) error {
 	p.Id, _ = block.GetMetadata("id")
-	accessKey, ok := block.GetMetadata(apiAccessKey)
-	if !ok {
-		return &schema.ErrNoSuchKey{Name: apiAccessKey}
-	}
-	accessToken, ok := block.GetMetadata(apiSecretKey)
-	if !ok {
+	// static keys are optional, the default credential chain is used otherwise
+	accessKey, hasAccessKey := block.GetMetadata(apiAccessKey)
+	accessToken, hasSecretKey := block.GetMetadata(apiSecretKey)
+	if hasAccessKey && !hasSecretKey {
 		return &schema.ErrNoSuchKey{Name: apiSecretKey}
 	}
+	if hasSecretKey && !hasAccessKey {
+		return &schema.ErrNoSuchKey{Name: apiAccessKey}
+	}
 	p.Token, _ = block.GetMetadata(sessionToken)
 	p.AccessKey = accessKey
 	p.SecretKey = accessToken
+	p.Profile, _ = block.GetMetadata(profile)
 
 	if assumeRoleArn, ok := block.GetMetadata(assumeRoleArn); ok {
 		p.AssumeRoleArn = assumeRoleArn
@@ -69,6 +75,22 @@ func (p *ProviderOptions) ParseOptionBlock(block schema.OptionBlock) error {
 		p.AssumeRoleName = assumeRoleName
 	}
 
+	if duration, ok := block.GetMetadata(assumeRoleDuration); ok {
+		value, err := time.ParseDuration(duration)
+		if err != nil {
+			return errors.Wrapf(err, "invalid %s", assumeRoleDuration)
+		}
+		p.AssumeRoleDuration = value
+	}
+
+	if value, ok := block.GetMetadata(accounts); ok {
+		parsed, err := parseAccounts(value)
+		if err != nil {
+			return err
+		}
+		p.Accounts = parsed
+	}
+
 	supportedServicesMap := make(map[string]struct{})
 	for _, s := range Services {
 		supportedServicesMap[s] = struct{}{}
@@ -89,28 +111,20 @@ func (p *ProviderOptions) ParseOptionBlock(block schema.OptionBlock) error {
 	}
 	p.Services = services
 
+	p.ConfigAggregator, _ = block.GetMetadata(configAggregator)
+	p.ConfigAggregatorRegion, _ = block.GetMetadata(configAggregatorRegion)
+
 	if accountIds, ok := block.GetMetadata(accountIds); ok {
-		p.AccountIds = sliceutil.Dedupe(strings.Split(accountIds, ","))
+		p.AccountIds = sliceutil.Dedupe(splitList(accountIds))
 	}
 	return nil
 }
 
 // Provider is a data provider for aws API
 type Provider struct {
-	options          *ProviderOptions
-	ec2Client        *ec2.EC2
-	route53Client    *route53.Route53
-	s3Client         *s3.S3
-	ecsClient        *ecs.ECS
-	eksClient        *eks.EKS
-	lambdaClient     *lambda.Lambda
-	apiGateway       *apigateway.APIGateway
-	albClient        *elbv2.ELBV2
-	elbClient        *elb.ELB
-	lightsailClient  *lightsail.Lightsail
-	cloudFrontClient *cloudfront.CloudFront
-	regions          *ec2.DescribeRegionsOutput
-	session          *session.Session
+	options *ProviderOptions
+	// configs are the configs of every enumerated account, base account first
+	configs []aws.Config
 }
 
 // New creates a new provider client for aws API
@@ -120,107 +134,35 @@ func New(block schema.OptionBlock) (*Provider, error) {
 		return nil, err
 	}
 
-	provider := &Provider{options: options}
-	config := aws.NewConfig()
-	config.WithRegion("us-east-1")
-	config.WithCredentials(credentials.NewStaticCredentials(options.AccessKey, options.SecretKey, options.Token))
-
-	var sess *session.Session
-	var err error
-
+	// credentials are resolved and roles assumed once, before enumeration
+	ctx := context.Background()
+	cfg, err := loadConfig(ctx, options)
+	if err != nil {
+		return nil, err
+	}
 	if options.AssumeRoleArn != "" {
-		stsSession, err := session.NewSession(config)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not establish session with AWS config")
-		}
-
-		stsClient := sts.New(stsSession)
-		roleInput := &sts.AssumeRoleInput{
-			RoleArn:         aws.String(options.AssumeRoleArn),
-			RoleSessionName: aws.String(options.AssumeRoleSessionName),
-			ExternalId:      aws.String(options.ExternalId),
-		}
-
-		assumeRoleOutput, err := stsClient.AssumeRole(roleInput)
+		cfg, err = assumeRoleConfig(ctx, cfg, options)
 		if err != nil {
-			return nil, errors.Wrap(err, "failed to assume role")
+			return nil, err
 		}
-
-		assumedCredentials := assumeRoleOutput.Credentials
-
-		sess, err = session.NewSession(&aws.Config{
-			Credentials: credentials.NewStaticCredentials(
-				*assumedCredentials.AccessKeyId,
-				*assumedCredentials.SecretAccessKey,
-				*assumedCredentials.SessionToken,
-			),
-			Region: config.Region,
-		})
-		if err != nil {
-			return nil, errors.Wrap(err, "could not assume role")
-		}
-	} else {
-		sess, err = session.NewSession(config)
-		if err != nil {
-			return nil, errors.Wrap(err, "could not establish a session")
-		}
-	}
-
-	provider.session = sess
-
-	rc := ec2.New(sess)
-	regions, err := rc.DescribeRegions(&ec2.DescribeRegionsInput{})
-	if err != nil {
-		return nil, errors.Wrap(err, "could not get list of regions")
-	}
-	provider.regions = regions
-
-	services := provider.options.Services
-	if services.Has("ec2") || services.Has("instance") {
-		provider.ec2Client = ec2.New(sess)
-	}
-	if services.Has("route53") {
-		provider.route53Client = route53.New(sess)
-	}
-	if services.Has("s3") {
-		provider.s3Client = s3.New(sess)
-	}
-	if services.Has("ecs") {
-		provider.ecsClient = ecs.New(sess)
-	}
-	if services.Has("eks") {
-		provider.eksClient = eks.New(sess)
-	}
-	if services.Has("lambda") {
-		provider.lambdaClient = lambda.New(sess)
 	}
-	if services.Has("apigateway") {
-		provider.apiGateway = apigateway.New(sess)
-	}
-	if services.Has("alb") {
-		provider.albClient = elbv2.New(sess)
-	}
-	if services.Has("elb") {
-		provider.elbClient = elb.New(sess)
-	}
-	if services.Has("lightsail") {
-		provider.lightsailClient = lightsail.New(sess)
-	}
-	if services.Has("cloudfront") {
-		provider.cloudFrontClient = cloudfront.New(sess)
-	}
-	return provider, nil
+	return &Provider{options: options, configs: accountConfigs(ctx, cfg, options)}, nil
 }
 
 const providerName = "aws"
 const apiAccessKey = "aws_access_key"
 const apiSecretKey = "aws_secret_key"
 const sessionToken = "aws_session_token"
+const profile = "aws_profile"
 const assumeRoleName = "assume_role_name"
 const assumeRoleArn = "assume_role_arn"
 const externalId = "external_id"
 const assumeRoleSessionName = "assume_role_session_name"
 const accountIds = "account_ids"
+const assumeRoleDuration = "assume_role_duration"
+const accounts = "accounts"
+const configAggregator = "config_aggregator"
+const configAggregatorRegion = "config_aggregator_region"
 
 // Name returns the name of the provider
 func (p *Provider) Name() string {
@@ -252,6 +194,30 @@ func worker(ctx context.Context, fn getResourcesFunc, ch chan<- result) {
 func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 	finalResources := schema.NewResources()
 
+	services := p.options.Services
+	// services covered by the aggregator are not listed from their own apis
+	if p.options.ConfigAggregator != "" {
+		aggregator := &configAggregatorProvider{options: *p.options, config: p.configs[0]}
+		resources, remaining, err := aggregator.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(resources)
+		if len(remaining) == 0 {
+			return finalResources, nil
+		}
+		services = remaining
+	}
+
+	// the direct providers only list the services left to them
+	options := *p.options
+	options.Services = services
+
+	regions, err := p.describeRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var workersWaitGroup sync.WaitGroup
 	results := make(chan result)
 
@@ -263,47 +229,100 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 		}()
 	}
 
-	if p.ec2Client != nil {
-		ec2provider := &instanceProvider{ec2Client: p.ec2Client, options: *p.options, session: p.session, regions: p.regions}
+	if services.Has("ec2") || services.Has("instance") {
+		ec2provider := &instanceProvider{options: options, configs: p.configs, regions: regions}
 		assignWorker(ec2provider.GetResource)
 	}
-	if p.route53Client != nil {
-		route53Provider := &route53Provider{route53: p.route53Client, options: *p.options, session: p.session}
+	if services.Has("eip") {
+		eipProvider := &eipProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(eipProvider.GetResource)
+	}
+	if services.Has("eni") {
+		eniProvider := &eniProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(eniProvider.GetResource)
+	}
+	if services.Has("route53") {
+		route53Provider := &route53Provider{options: options, configs: p.configs}
 		assignWorker(route53Provider.GetResource)
 	}
-	if p.s3Client != nil {
-		s3Provider := &s3Provider{s3: p.s3Client, options: *p.options, session: p.session}
+	if services.Has("s3") {
+		s3Provider := &s3Provider{options: options, configs: p.configs}
 		assignWorker(s3Provider.GetResource)
 	}
-	if p.ecsClient != nil {
-		ecsProvider := &ecsProvider{ecsClient: p.ecsClient, options: *p.options, session: p.session, regions: p.regions}
+	if services.Has("ecs") {
+		ecsProvider := &ecsProvider{options: options, configs: p.configs, regions: regions}
 		assignWorker(ecsProvider.GetResource)
 	}
-	if p.eksClient != nil {
-		eksProvider := &eksProvider{eksClient: p.eksClient, options: *p.options, session: p.session, regions: p.regions}
+	if services.Has("eks") {
+		eksProvider := &eksProvider{options: options, configs: p.configs, regions: regions}
 		assignWorker(eksProvider.GetResource)
 	}
-	if p.apiGateway != nil && p.lambdaClient != nil {
-		lamdaAndApiGatewayProvider := &lambdaAndapiGatewayProvider{apiGateway: p.apiGateway, lambdaClient: p.lambdaClient, options: *p.options, session: p.session, regions: p.regions}
+	if services.Has("apigateway") || services.Has("lambda") {
+		lamdaAndApiGatewayProvider := &lambdaAndapiGatewayProvider{options: options, configs: p.configs, regions: regions}
 		assignWorker(lamdaAndApiGatewayProvider.GetResource)
 	}
-	if p.albClient != nil {
-		albProvider := &elbV2Provider{albClient: p.albClient, options: *p.options, session: p.session, regions: p.regions}
+	if services.Has("appsync") {
+		appsyncProvider := &appsyncProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(appsyncProvider.GetResource)
+	}
+	if services.Has("rds") {
+		rdsProvider := &rdsProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(rdsProvider.GetResource)
+	}
+	if services.Has("redshift") {
+		redshiftProvider := &redshiftProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(redshiftProvider.GetResource)
+	}
+	if services.Has("opensearch") {
+		opensearchProvider := &opensearchProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(opensearchProvider.GetResource)
+	}
+	if services.Has("elasticache") {
+		elasticacheProvider := &elasticacheProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(elasticacheProvider.GetResource)
+	}
+	if services.Has("docdb") {
+		docdbProvider := &docdbProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(docdbProvider.GetResource)
+	}
+	if services.Has("elasticbeanstalk") {
+		elasticBeanstalkProvider := &elasticBeanstalkProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(elasticBeanstalkProvider.GetResource)
+	}
+	if services.Has("apprunner") {
+		appRunnerProvider := &appRunnerProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(appRunnerProvider.GetResource)
+	}
+	if services.Has("amplify") {
+		amplifyProvider := &amplifyProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(amplifyProvider.GetResource)
+	}
+	if services.Has("acm") {
+		acmProvider := &acmProvider{options: options, configs: p.configs, regions: regions}
+		assignWorker(acmProvider.GetResource)
+	}
+	if services.Has("alb") {
+		albProvider := &elbV2Provider{options: options, configs: p.configs, regions: regions}
 		assignWorker(albProvider.GetResource)
 	}
-	if p.elbClient != nil {
-		elbProvider := &elbProvider{elbClient: p.elbClient, options: *p.options, session: p.session, regions: p.regions}
+	if services.Has("elb") {
+		elbProvider := &elbProvider{options: options, configs: p.configs, regions: regions}
 		assignWorker(elbProvider.GetResource)
 	}
-	if p.lightsailClient != nil {
-		lsRegions, err := p.lightsailClient.GetRegions(&lightsail.GetRegionsInput{})
+	if services.Has("lightsail") {
+		lsClient := lightsail.NewFromConfig(p.configs[0])
+		lsRegions, err := lsClient.GetRegions(ctx, &lightsail.GetRegionsInput{})
 		if err == nil {
-			lightsailProvider := &lightsailProvider{lsClient: p.lightsailClient, options: *p.options, session: p.session, regions: lsRegions.Regions}
+			regionNames := make([]string, 0, len(lsRegions.Regions))
+			for _, region := range lsRegions.Regions {
+				regionNames = append(regionNames, string(region.Name))
+			}
+			lightsailProvider := &lightsailProvider{options: options, configs: p.configs, regions: regionNames}
 			assignWorker(lightsailProvider.GetResource)
 		}
 	}
-	if p.cloudFrontClient != nil {
-		cloudfrontProvider := &cloudfrontProvider{cloudFrontClient: p.cloudFrontClient, options: *p.options, session: p.session}
+	if services.Has("cloudfront") {
+		cloudfrontProvider := &cloudfrontProvider{options: options, configs: p.configs}
 		assignWorker(cloudfrontProvider.GetResource)
 	}
 
@@ -321,77 +340,108 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 	return finalResources, nil
 }
 
-// Verify checks if the provider is valid using simple API calls
-func (p *Provider) Verify(ctx context.Context) error {
-	var success bool
-
-	// Try EC2 DescribeRegions (lightweight operation)
-	if p.ec2Client != nil {
-		_, err := p.ec2Client.DescribeRegions(&ec2.DescribeRegionsInput{})
-		if err == nil {
-			success = true
-		}
-	}
-
-	// Try other services with simple operations if EC2 failed
-	if !success && p.route53Client != nil {
-		_, err := p.route53Client.ListHostedZones(&route53.ListHostedZonesInput{})
-		if err == nil {
-			success = true
-		}
-	}
-
-	if !success && p.s3Client != nil {
-		_, err := p.s3Client.ListBuckets(&s3.ListBucketsInput{})
-		if err == nil {
-			success = true
-		}
-	}
-
-	if !success && p.lambdaClient != nil {
-		_, err := p.lambdaClient.ListFunctions(&lambda.ListFunctionsInput{})
-		if err == nil {
-			success = true
-		}
+// describeRegions returns the regions enabled for the base account
+func (p *Provider) describeRegions(ctx context.Context) ([]string, error) {
+	output, err := ec2.NewFromConfig(p.configs[0]).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get list of regions")
 	}
-
-	if !success && p.apiGateway != nil {
-		_, err := p.apiGateway.GetRestApis(&apigateway.GetRestApisInput{})
-		if err == nil {
-			success = true
-		}
+	regions := make([]string, 0, len(output.Regions))
+	for _, region := range output.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
 	}
+	return regions, nil
+}
 
-	if !success && p.albClient != nil {
-		_, err := p.albClient.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{})
-		if err == nil {
-			success = true
-		}
-	}
+// Verify checks if the provider is valid using simple API calls
+func (p *Provider) Verify(ctx context.Context) error {
+	cfg := p.configs[0]
+	services := p.options.Services
 
-	if !success && p.elbClient != nil {
-		_, err := p.elbClient.DescribeLoadBalancers(&elb.DescribeLoadBalancersInput{})
-		if err == nil {
-			success = true
+	if p.options.ConfigAggregator != "" {
+		_, err := newClient(cfg, p.options.ConfigAggregatorRegion, configservice.NewFromConfig).DescribeConfigurationAggregators(ctx, &configservice.DescribeConfigurationAggregatorsInput{
+			ConfigurationAggregatorNames: []string{p.options.ConfigAggregator},
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to verify AWS credentials: could not access config aggregator")
 		}
+		return nil
 	}
 
-	if !success && p.lightsailClient != nil {
-		_, err := p.lightsailClient.GetRegions(&lightsail.GetRegionsInput{})
-		if err == nil {
-			success = true
+	// services are tried in order with lightweight operations until one succeeds
+	checks := []struct {
+		enabled bool
+		check   func() error
+	}{
+		{services.Has("ec2") || services.Has("instance") || services.Has("eip") || services.Has("eni"), func() error {
+			_, err := ec2.NewFromConfig(cfg).DescribeRegions(ctx, &ec2.DescribeRegionsInput{})
+			return err
+		}},
+		{services.Has("route53"), func() error {
+			_, err := route53.NewFromConfig(cfg).ListHostedZones(ctx, &route53.ListHostedZonesInput{})
+			return err
+		}},
+		{services.Has("s3"), func() error {
+			_, err := s3.NewFromConfig(cfg).ListBuckets(ctx, &s3.ListBucketsInput{})
+			return err
+		}},
+		{services.Has("lambda"), func() error {
+			_, err := lambda.NewFromConfig(cfg).ListFunctions(ctx, &lambda.ListFunctionsInput{})
+			return err
+		}},
+		{services.Has("apigateway"), func() error {
+			_, err := apigateway.NewFromConfig(cfg).GetRestApis(ctx, &apigateway.GetRestApisInput{})
+			return err
+		}},
+		{services.Has("alb"), func() error {
+			_, err := elasticloadbalancingv2.NewFromConfig(cfg).DescribeLoadBalancers(ctx, &elasticloadbalancingv2.DescribeLoadBalancersInput{})
+			return err
+		}},
+		{services.Has("elb"), func() error {
+			_, err := elasticloadbalancing.NewFromConfig(cfg).DescribeLoadBalancers(ctx, &elasticloadbalancing.DescribeLoadBalancersInput{})
+			return err
+		}},
+		{services.Has("lightsail"), func() error {
+			_, err := lightsail.NewFromConfig(cfg).GetRegions(ctx, &lightsail.GetRegionsInput{})
+			return err
+		}},
+		{services.Has("cloudfront"), func() error {
+			_, err := cloudfront.NewFromConfig(cfg).ListDistributions(ctx, &cloudfront.ListDistributionsInput{})
+			return err
+		}},
+	}
+	for _, item := range checks {
+		if item.enabled && item.check() == nil {
+			return nil
 		}
 	}
+	return errors.New("failed to verify AWS credentials: no accessible services found")
+}
 
-	if !success && p.cloudFrontClient != nil {
-		_, err := p.cloudFrontClient.ListDistributions(&cloudfront.ListDistributionsInput{})
-		if err == nil {
-			success = true
+// cleanMetadata removes the empty values of the resource metadata
+func cleanMetadata(metadata map[string]string) map[string]string {
+	for key, value := range metadata {
+		if value == "" {
+			delete(metadata, key)
 		}
 	}
+	return metadata
+}
 
-	if success {
-		return nil
+// endpointResource returns the resource of a managed service endpoint, the
+// publicly_accessible metadata reports whether the service exposes it publicly.
+func endpointResource(id, service, host string, port int64, publiclyAccessible bool, metadata map[string]string) *schema.Resource {
+	metadata["endpoint"] = host
+	metadata["publicly_accessible"] = strconv.FormatBool(publiclyAccessible)
+	if port > 0 {
+		metadata["port"] = strconv.FormatInt(port, 10)
+	}
+	return &schema.Resource{
+		ID:       id,
+		Provider: providerName,
+		DNSName:  host,
+		Public:   publiclyAccessible,
+		Service:  service,
+		Metadata: cleanMetadata(metadata),
 	}
-	return errors.New("failed to verify AWS credentials: no accessible services found")
 }
@@ -0,0 +1,292 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+// awsStandIn stands in for the ec2 (query protocol) and route53 (rest-xml)
+// endpoints, serving every listing over two pages.
+type awsStandIn struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *awsStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/xml")
+	if strings.HasPrefix(r.URL.Path, "/2013-04-01/") {
+		s.serveRoute53(w, r)
+		return
+	}
+	_ = r.ParseForm()
+	s.record(r.Form.Get("Action") + " " + r.Form.Get("NextToken"))
+
+	switch r.Form.Get("Action") {
+	case "DescribeRegions":
+		fmt.Fprint(w, `<DescribeRegionsResponse><requestId>1</requestId><regionInfo>
+			<item><regionName>us-east-1</regionName></item>
+		</regionInfo></DescribeRegionsResponse>`)
+	case "DescribeInstances":
+		if r.Form.Get("NextToken") == "" {
+			fmt.Fprint(w, `<DescribeInstancesResponse><requestId>2</requestId><reservationSet>
+				<item><instancesSet><item><instanceId>i-1</instanceId><privateIpAddress>10.0.0.1</privateIpAddress><ipAddress>52.0.0.1</ipAddress></item></instancesSet></item>
+			</reservationSet><nextToken>page-2</nextToken></DescribeInstancesResponse>`)
+			return
+		}
+		fmt.Fprint(w, `<DescribeInstancesResponse><requestId>3</requestId><reservationSet>
+			<item><instancesSet><item><instanceId>i-2</instanceId><privateIpAddress>10.0.0.2</privateIpAddress><ipAddress>52.0.0.2</ipAddress></item></instancesSet></item>
+		</reservationSet></DescribeInstancesResponse>`)
+	case "DescribeDBInstances":
+		fmt.Fprint(w, `<DescribeDBInstancesResponse><DescribeDBInstancesResult><DBInstances>
+			<DBInstance><DBInstanceIdentifier>db-1</DBInstanceIdentifier><DBClusterIdentifier>db</DBClusterIdentifier><Engine>aurora-postgresql</Engine><PubliclyAccessible>true</PubliclyAccessible><Endpoint><Address>db-1.abc.us-east-1.rds.amazonaws.com</Address><Port>5432</Port></Endpoint></DBInstance>
+		</DBInstances></DescribeDBInstancesResult></DescribeDBInstancesResponse>`)
+	case "DescribeDBClusters":
+		fmt.Fprint(w, `<DescribeDBClustersResponse><DescribeDBClustersResult><DBClusters>
+			<DBCluster><DBClusterIdentifier>db</DBClusterIdentifier><Engine>aurora-postgresql</Engine><Endpoint>db.cluster-abc.us-east-1.rds.amazonaws.com</Endpoint><ReaderEndpoint>db.cluster-ro-abc.us-east-1.rds.amazonaws.com</ReaderEndpoint><Port>5432</Port>
+				<DBClusterMembers><DBClusterMember><DBInstanceIdentifier>db-1</DBInstanceIdentifier><IsClusterWriter>true</IsClusterWriter></DBClusterMember></DBClusterMembers></DBCluster>
+		</DBClusters></DescribeDBClustersResult></DescribeDBClustersResponse>`)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `<Response><Errors><Error><Code>InvalidAction</Code></Error></Errors></Response>`)
+	}
+}
+
+func (s *awsStandIn) serveRoute53(w http.ResponseWriter, r *http.Request) {
+	s.record(r.URL.Path + " " + r.URL.Query().Get("name"))
+
+	switch {
+	case r.URL.Path == "/2013-04-01/hostedzone":
+		fmt.Fprint(w, `<ListHostedZonesResponse><HostedZones>
+			<HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name><CallerReference>ref</CallerReference></HostedZone>
+		</HostedZones><IsTruncated>false</IsTruncated><MaxItems>100</MaxItems></ListHostedZonesResponse>`)
+	case r.URL.Path == "/2013-04-01/hostedzone/Z1/rrset" && r.URL.Query().Get("name") == "":
+		fmt.Fprint(w, `<ListResourceRecordSetsResponse><ResourceRecordSets>
+			<ResourceRecordSet><Name>www.example.com.</Name><Type>A</Type><TTL>300</TTL><ResourceRecords><ResourceRecord><Value>54.0.0.1</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		</ResourceRecordSets><IsTruncated>true</IsTruncated><NextRecordName>api.example.com.</NextRecordName><NextRecordType>CNAME</NextRecordType><MaxItems>1</MaxItems></ListResourceRecordSetsResponse>`)
+	case r.URL.Path == "/2013-04-01/hostedzone/Z1/rrset":
+		fmt.Fprint(w, `<ListResourceRecordSetsResponse><ResourceRecordSets>
+			<ResourceRecordSet><Name>api.example.com.</Name><Type>CNAME</Type><TTL>300</TTL><ResourceRecords><ResourceRecord><Value>api.example.net</Value></ResourceRecord></ResourceRecords></ResourceRecordSet>
+		</ResourceRecordSets><IsTruncated>false</IsTruncated><MaxItems>1</MaxItems></ListResourceRecordSetsResponse>`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *awsStandIn) record(request string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, strings.TrimSpace(request))
+}
+
+func newStandInProvider(t *testing.T, services ...string) (*Provider, *awsStandIn) {
+	standIn := &awsStandIn{}
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	serviceMap := make(schema.ServiceMap)
+	for _, service := range services {
+		serviceMap[service] = struct{}{}
+	}
+	cfg := aws.Config{
+		Region:       globalRegion,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKID", "SECRET", ""),
+		BaseEndpoint: aws.String(server.URL),
+		Retryer:      func() aws.Retryer { return aws.NopRetryer{} },
+	}
+	return &Provider{options: &ProviderOptions{Id: "test", Services: serviceMap}, configs: []aws.Config{cfg}}, standIn
+}
+
+func TestResourcesPagination(t *testing.T) {
+	provider, standIn := newStandInProvider(t, "instance", "route53")
+
+	resources, err := provider.Resources(context.Background())
+	require.NoError(t, err)
+
+	var privateIPs, publicIPs, hosts []string
+	for _, resource := range resources.Items {
+		if resource.PrivateIpv4 != "" {
+			privateIPs = append(privateIPs, resource.PrivateIpv4)
+		}
+		if resource.PublicIPv4 != "" {
+			publicIPs = append(publicIPs, resource.PublicIPv4)
+		}
+		if resource.DNSName != "" {
+			hosts = append(hosts, resource.DNSName)
+		}
+	}
+	require.ElementsMatch(t, []string{"10.0.0.1", "10.0.0.2"}, privateIPs)
+	require.ElementsMatch(t, []string{"52.0.0.1", "52.0.0.2", "54.0.0.1"}, publicIPs)
+	require.ElementsMatch(t, []string{"www.example.com", "api.example.com"}, hosts)
+
+	require.Contains(t, standIn.requests, "DescribeInstances page-2")
+	require.Contains(t, standIn.requests, "/2013-04-01/hostedzone/Z1/rrset api.example.com.")
+}
+
+func TestResourcesContextCanceled(t *testing.T) {
+	provider, standIn := newStandInProvider(t, "instance", "route53")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := provider.Resources(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, standIn.requests)
+
+	instances := &instanceProvider{options: *provider.options, configs: provider.configs, regions: []string{globalRegion}}
+	_, err = instances.getEC2Resources(ctx, provider.configs[0], globalRegion)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, standIn.requests)
+}
+
+func TestRecordSetResources(t *testing.T) {
+	provider := &route53Provider{options: ProviderOptions{Id: "test"}}
+	zone := map[string]string{"zone": "example.com", "zone_id": "Z1", "private_zone": "false"}
+
+	weighted := provider.recordSetResources(&types.ResourceRecordSet{
+		Name:            aws.String("www.example.com."),
+		Type:            types.RRTypeA,
+		SetIdentifier:   aws.String("blue"),
+		Weight:          aws.Int64(10),
+		ResourceRecords: []types.ResourceRecord{{Value: aws.String("54.0.0.1")}, {Value: aws.String("54.0.0.2")}},
+	}, zone, true)
+	var ips []string
+	for _, resource := range weighted.Items {
+		require.Equal(t, "weighted", resource.Metadata["routing_policy"])
+		require.Equal(t, "blue", resource.Metadata["set_identifier"])
+		if resource.PublicIPv4 != "" {
+			ips = append(ips, resource.PublicIPv4)
+		}
+	}
+	require.ElementsMatch(t, []string{"54.0.0.1", "54.0.0.2"}, ips)
+
+	alias := provider.recordSetResources(&types.ResourceRecordSet{
+		Name:        aws.String("app.example.com."),
+		Type:        types.RRTypeA,
+		AliasTarget: &types.AliasTarget{DNSName: aws.String("dualstack.web-1.us-east-1.elb.amazonaws.com."), HostedZoneId: aws.String("Z35SXDOTRQ7X7K")},
+	}, zone, true)
+	var hosts []string
+	for _, resource := range alias.Items {
+		require.Equal(t, "load_balancer", resource.Metadata["alias_target_type"])
+		hosts = append(hosts, resource.DNSName)
+	}
+	require.ElementsMatch(t, []string{"app.example.com", "web-1.us-east-1.elb.amazonaws.com"}, hosts)
+
+	// the shared s3 website endpoint is only kept in the metadata
+	website := provider.recordSetResources(&types.ResourceRecordSet{
+		Name:        aws.String("static.example.com."),
+		Type:        types.RRTypeA,
+		AliasTarget: &types.AliasTarget{DNSName: aws.String("s3-website-us-east-1.amazonaws.com."), HostedZoneId: aws.String("Z3AQBSTGFYJSTF")},
+	}, zone, true)
+	require.Len(t, website.Items, 1)
+	require.Equal(t, "static.example.com", website.Items[0].DNSName)
+	require.Equal(t, "s3_website", website.Items[0].Metadata["alias_target_type"])
+	require.Equal(t, "s3-website-us-east-1.amazonaws.com", website.Items[0].Metadata["alias_target"])
+}
+
+func TestRDSResources(t *testing.T) {
+	provider, _ := newStandInProvider(t)
+	rp := &rdsProvider{options: *provider.options}
+
+	// the cluster endpoints listing is not served, the instances and
+	// clusters already listed are still returned
+	resources, err := rp.listRDSResources(context.Background(), provider.configs[0], "us-east-1")
+	require.NoError(t, err)
+
+	endpointTypes := make(map[string]string)
+	for _, resource := range resources.Items {
+		// aurora clusters are public when one of their instances is
+		require.True(t, resource.Public, resource.DNSName)
+		endpointTypes[resource.DNSName] = resource.Metadata["endpoint_type"]
+	}
+	require.Equal(t, map[string]string{
+		"db-1.abc.us-east-1.rds.amazonaws.com":          "instance",
+		"db.cluster-abc.us-east-1.rds.amazonaws.com":    "writer",
+		"db.cluster-ro-abc.us-east-1.rds.amazonaws.com": "reader",
+	}, endpointTypes)
+}
+
+func TestConfigAggregatorResources(t *testing.T) {
+	provider := &configAggregatorProvider{options: ProviderOptions{Id: "test"}}
+	decode := func(query configQuery, result string) *schema.Resources {
+		item := &configItem{}
+		require.NoError(t, json.Unmarshal([]byte(result), item))
+		resources, err := query.resources(provider, item)
+		require.NoError(t, err)
+		return resources
+	}
+	queries := make(map[string]configQuery)
+	for _, query := range configQueries {
+		queries[query.resourceType] = query
+	}
+
+	require.Equal(t, "SELECT accountId, awsRegion, resourceId, resourceName, configuration.dnsname WHERE resourceType = 'AWS::ElasticLoadBalancing::LoadBalancer'", queries["AWS::ElasticLoadBalancing::LoadBalancer"].expression())
+	classic := decode(queries["AWS::ElasticLoadBalancing::LoadBalancer"], `{"accountId":"111111111111","awsRegion":"us-east-1","resourceName":"web","configuration":{"dnsname":"web-1.us-east-1.elb.amazonaws.com"}}`)
+	require.Len(t, classic.Items, 1)
+	require.Equal(t, "web-1.us-east-1.elb.amazonaws.com", classic.Items[0].DNSName)
+	require.Equal(t, "elb", classic.Items[0].Service)
+
+	distribution := decode(queries["AWS::CloudFront::Distribution"], `{"resourceId":"E1","configuration":{"domainName":"d1.cloudfront.net","distributionConfig":{"enabled":true,"aliases":{"items":["www.example.com"]}}}}`)
+	var hosts []string
+	for _, resource := range distribution.Items {
+		require.Equal(t, "E1", resource.Metadata["distribution_id"])
+		hosts = append(hosts, resource.DNSName)
+	}
+	require.ElementsMatch(t, []string{"d1.cloudfront.net", "www.example.com"}, hosts)
+
+	bucket := decode(queries["AWS::S3::Bucket"], `{"awsRegion":"eu-west-1","resourceName":"assets"}`)
+	require.Len(t, bucket.Items, 1)
+	require.Equal(t, "assets.s3.eu-west-1.amazonaws.com", bucket.Items[0].DNSName)
+
+	// aurora clusters do not record their flag, it is taken from their instances
+	instance := decode(queries["AWS::RDS::DBInstance"], `{"accountId":"111111111111","awsRegion":"us-east-1","resourceName":"db-1","configuration":{"endpoint":{"address":"db-1.abc.us-east-1.rds.amazonaws.com","port":5432},"publiclyAccessible":true,"dBClusterIdentifier":"db"}}`)
+	require.Len(t, instance.Items, 1)
+	cluster := decode(queries["AWS::RDS::DBCluster"], `{"accountId":"111111111111","awsRegion":"us-east-1","resourceName":"db","configuration":{"endpoint":"db.cluster-abc.us-east-1.rds.amazonaws.com","readerEndpoint":"db.cluster-ro-abc.us-east-1.rds.amazonaws.com","port":5432,"engine":"aurora-postgresql"}}`)
+	endpointTypes := make(map[string]string)
+	for _, resource := range cluster.Items {
+		require.Equal(t, "db", resource.Metadata["cluster"])
+		require.True(t, resource.Public)
+		endpointTypes[resource.DNSName] = resource.Metadata["endpoint_type"]
+	}
+	require.Equal(t, map[string]string{
+		"db.cluster-abc.us-east-1.rds.amazonaws.com":    "writer",
+		"db.cluster-ro-abc.us-east-1.rds.amazonaws.com": "reader",
+	}, endpointTypes)
+}
+
+func TestCertificateResources(t *testing.T) {
+	provider := &acmProvider{options: ProviderOptions{Id: "test"}}
+
+	for _, names := range [][]string{
+		{"*.example.com", "example.com", "*.internal.example.com"},
+		{"example.com", "*.example.com", "*.internal.example.com"},
+	} {
+		resources := provider.certificateResources(names, map[string]string{"certificate_arn": "arn:aws:acm:us-east-1:111111111111:certificate/1"})
+		require.Len(t, resources.Items, 2)
+		require.Equal(t, "example.com", resources.Items[0].DNSName)
+		require.Equal(t, "arn:aws:acm:us-east-1:111111111111:certificate/1", resources.Items[0].Metadata["certificate_arn"])
+		require.Empty(t, resources.Items[0].Metadata["wildcard"])
+		require.ElementsMatch(t, []string{"*.example.com", "*.internal.example.com"}, strings.Split(resources.Items[0].Metadata["wildcard_sans"], ","))
+
+		require.Equal(t, "internal.example.com", resources.Items[1].DNSName)
+		require.Equal(t, "true", resources.Items[1].Metadata["wildcard"])
+		require.Equal(t, "*.internal.example.com", resources.Items[1].Metadata["wildcard_san"])
+		require.Equal(t, "arn:aws:acm:us-east-1:111111111111:certificate/1", resources.Items[1].Metadata["certificate_arn"])
+	}
+
+	wildcardOnly := provider.certificateResources([]string{"*.example.com"}, map[string]string{})
+	require.Len(t, wildcardOnly.Items, 1)
+	require.Equal(t, "example.com", wildcardOnly.Items[0].DNSName)
+	require.Equal(t, "true", wildcardOnly.Items[0].Metadata["wildcard"])
+	require.Equal(t, "*.example.com", wildcardOnly.Items[0].Metadata["wildcard_san"])
+}
@@ -0,0 +1,77 @@
+package aws
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// globalRegion is the region used for the global services (route53,
+// cloudfront, iam) and the initial calls of the provider.
+const globalRegion = "us-east-1"
+
+// newClient creates a service client for an account config in the
+// given region, or in the region of the config if empty.
+func newClient[T, O any](cfg aws.Config, region string, newFromConfig func(aws.Config, ...func(*O)) T) T {
+	if region != "" && region != cfg.Region {
+		cfg = cfg.Copy()
+		cfg.Region = region
+	}
+	return newFromConfig(cfg)
+}
+
+// newClients creates a service client for every account config, in the
+// given region or in the region of the config if empty.
+func newClients[T, O any](configs []aws.Config, region string, newFromConfig func(aws.Config, ...func(*O)) T) []T {
+	clients := make([]T, 0, len(configs))
+	for _, cfg := range configs {
+		clients = append(clients, newClient(cfg, region, newFromConfig))
+	}
+	return clients
+}
+
+// regionalLister lists the resources of a service for an account config in a region
+type regionalLister func(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error)
+
+// listAllRegions runs the lister concurrently for every account config and
+// region, merging the results. Failures of an account or region are skipped.
+func listAllRegions(ctx context.Context, configs []aws.Config, regions []string, lister regionalLister) *schema.Resources {
+	list := schema.NewResources()
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	for _, region := range regions {
+		for _, cfg := range configs {
+			wg.Add(1)
+
+			go func(cfg aws.Config, region string) {
+				defer wg.Done()
+
+				if resources, err := lister(ctx, cfg, region); err == nil {
+					mu.Lock()
+					list.Merge(resources)
+					mu.Unlock()
+				}
+			}(cfg, region)
+		}
+	}
+	wg.Wait()
+	return list
+}
+
+// listAllAccounts runs the lister of a global service concurrently for every account config
+func listAllAccounts(ctx context.Context, configs []aws.Config, lister regionalLister) *schema.Resources {
+	return listAllRegions(ctx, configs, []string{globalRegion}, lister)
+}
+
+// joinEnums joins the string values of a list of sdk enums
+func joinEnums[T ~string](values []T) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		parts = append(parts, string(value))
+	}
+	return strings.Join(parts, ",")
+}
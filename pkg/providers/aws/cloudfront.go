@@ -2,22 +2,22 @@ package aws
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"maps"
+	"strconv"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	"github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
 
-// cloudfrontProvider is a provider for AWS CloudFront API
+// cloudfrontProvider is a provider for AWS CloudFront distributions, their
+// alternate domain names and origins
 type cloudfrontProvider struct {
-	options          ProviderOptions
-	cloudFrontClient *cloudfront.CloudFront
-	session          *session.Session
+	options ProviderOptions
+	configs []aws.Config
 }
 
 func (cp *cloudfrontProvider) name() string {
@@ -26,75 +26,132 @@ func (cp *cloudfrontProvider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (cp *cloudfrontProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, client := range cp.getCloudfrontClients() {
-		wg.Add(1)
-
-		go func(cloudfrontClient *cloudfront.CloudFront) {
-			defer wg.Done()
-
-			if resources, err := cp.listCloudFrontResources(cloudfrontClient); err == nil {
-				mu.Lock()
-				list.Merge(resources)
-				mu.Unlock()
-			}
-		}(client)
-	}
-	wg.Wait()
-	return list, nil
+	return listAllAccounts(ctx, cp.configs, cp.listCloudFrontResources), nil
 }
 
-func (cp *cloudfrontProvider) listCloudFrontResources(cloudFrontClient *cloudfront.CloudFront) (*schema.Resources, error) {
+func (cp *cloudfrontProvider) listCloudFrontResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
 	list := schema.NewResources()
-	req := &cloudfront.ListDistributionsInput{MaxItems: aws.Int64(400)}
-	for {
-		distributions, err := cloudFrontClient.ListDistributions(req)
+	cloudFrontClient := newClient(cfg, region, cloudfront.NewFromConfig)
+
+	paginator := cloudfront.NewListDistributionsPaginator(cloudFrontClient, &cloudfront.ListDistributionsInput{MaxItems: aws.Int32(400)})
+	for paginator.HasMorePages() {
+		distributions, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not list distributions")
 		}
+		if distributions.DistributionList == nil {
+			break
+		}
 
 		for _, distribution := range distributions.DistributionList.Items {
-			resource := &schema.Resource{
-				Provider: "aws",
-				ID:       aws.StringValue(distribution.Id),
-				DNSName:  aws.StringValue(distribution.DomainName),
-				Public:   true,
-				Service:  cp.name(),
+			metadata := map[string]string{
+				"distribution_id":     aws.ToString(distribution.Id),
+				"distribution_domain": aws.ToString(distribution.DomainName),
+				"enabled":             strconv.FormatBool(aws.ToBool(distribution.Enabled)),
+				"status":              aws.ToString(distribution.Status),
+				"web_acl_id":          aws.ToString(distribution.WebACLId),
+				"functions":           strings.Join(distributionFunctions(&distribution), ","),
+			}
+			if distribution.ViewerCertificate != nil {
+				metadata["certificate_arn"] = aws.ToString(distribution.ViewerCertificate.ACMCertificateArn)
+			}
+
+			hosts := []string{aws.ToString(distribution.DomainName)}
+			if distribution.Aliases != nil {
+				hosts = append(hosts, distribution.Aliases.Items...)
+			}
+			for _, host := range hosts {
+				list.Append(cp.distributionResource(&distribution, host, metadata))
+			}
+
+			// origins are reported so that what sits behind the cdn is visible
+			if distribution.Origins == nil {
+				continue
+			}
+			for _, origin := range distribution.Origins.Items {
+				originMetadata := map[string]string{
+					"resource_type": "origin",
+					"origin_id":     aws.ToString(origin.Id),
+					"origin_type":   cloudfrontOriginType(&origin),
+					"origin_path":   aws.ToString(origin.OriginPath),
+				}
+				if origin.CustomOriginConfig != nil {
+					originMetadata["origin_protocol_policy"] = string(origin.CustomOriginConfig.OriginProtocolPolicy)
+				}
+				for key, value := range metadata {
+					originMetadata[key] = value
+				}
+				list.Append(cp.distributionResource(&distribution, aws.ToString(origin.DomainName), originMetadata))
 			}
-			list.Append(resource)
-		}
-		if aws.StringValue(distributions.DistributionList.NextMarker) == "" {
-			break
 		}
-		req.SetMarker(aws.StringValue(distributions.DistributionList.NextMarker))
 	}
 	return list, nil
 }
 
-func (cp *cloudfrontProvider) getCloudfrontClients() []*cloudfront.CloudFront {
-	cloudfrontClients := make([]*cloudfront.CloudFront, 0)
-	cloudfrontClients = append(cloudfrontClients, cp.cloudFrontClient)
-
-	if cp.options.AssumeRoleName == "" || len(cp.options.AccountIds) < 1 {
-		return cloudfrontClients
+func (cp *cloudfrontProvider) distributionResource(distribution *types.DistributionSummary, host string, metadata map[string]string) *schema.Resource {
+	return &schema.Resource{
+		Provider: "aws",
+		ID:       aws.ToString(distribution.Id),
+		DNSName:  host,
+		Public:   true,
+		Service:  cp.name(),
+		Metadata: cleanMetadata(maps.Clone(metadata)),
 	}
+}
 
-	for _, accountId := range cp.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, cp.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(cp.session, roleARN)
+// cloudfrontOriginType returns the kind of service an origin points to
+func cloudfrontOriginType(origin *types.Origin) string {
+	domain := aws.ToString(origin.DomainName)
+	switch {
+	case strings.Contains(domain, ".s3-website"):
+		return "s3_website"
+	case origin.S3OriginConfig != nil || strings.Contains(domain, ".s3."):
+		return "s3"
+	case strings.HasSuffix(domain, ".elb.amazonaws.com"):
+		return "load_balancer"
+	case strings.Contains(domain, ".execute-api."):
+		return "apigateway"
+	case strings.Contains(domain, ".lambda-url."):
+		return "lambda"
+	case strings.Contains(domain, ".mediastore.") || strings.Contains(domain, ".mediapackage."):
+		return "media"
+	default:
+		return "custom"
+	}
+}
 
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      aws.String("us-east-1"),
-			Credentials: creds,
-		})
-		if err != nil {
-			continue
+// distributionFunctions returns the cloudfront functions and lambda@edge
+// functions associated with the cache behaviors of a distribution
+func distributionFunctions(distribution *types.DistributionSummary) []string {
+	var functions []string
+	seen := make(map[string]struct{})
+	add := func(arn string) {
+		if _, ok := seen[arn]; ok || arn == "" {
+			return
 		}
+		seen[arn] = struct{}{}
+		functions = append(functions, arn)
+	}
+	addAssociations := func(functionAssociations *types.FunctionAssociations, lambdaAssociations *types.LambdaFunctionAssociations) {
+		if functionAssociations != nil {
+			for _, association := range functionAssociations.Items {
+				add(aws.ToString(association.FunctionARN))
+			}
+		}
+		if lambdaAssociations != nil {
+			for _, association := range lambdaAssociations.Items {
+				add(aws.ToString(association.LambdaFunctionARN))
+			}
+		}
+	}
 
-		cloudfrontClients = append(cloudfrontClients, cloudfront.New(assumeSession))
+	if behavior := distribution.DefaultCacheBehavior; behavior != nil {
+		addAssociations(behavior.FunctionAssociations, behavior.LambdaFunctionAssociations)
+	}
+	if distribution.CacheBehaviors != nil {
+		for _, behavior := range distribution.CacheBehaviors.Items {
+			addAssociations(behavior.FunctionAssociations, behavior.LambdaFunctionAssociations)
+		}
 	}
-	return cloudfrontClients
+	return functions
 }
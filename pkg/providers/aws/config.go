@@ -0,0 +1,495 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/configservice"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// configItem is a row returned by an aggregator advanced query, the selected
+// configuration properties being nested under configuration
+type configItem struct {
+	AccountID     string          `json:"accountId"`
+	AwsRegion     string          `json:"awsRegion"`
+	ResourceID    string          `json:"resourceId"`
+	ResourceName  string          `json:"resourceName"`
+	Configuration json.RawMessage `json:"configuration"`
+}
+
+// configQuery is the advanced query of a resource type and the function
+// turning its rows into the resources of a service
+type configQuery struct {
+	services     []string
+	resourceType string
+	properties   []string
+	resources    func(cp *configAggregatorProvider, item *configItem) (*schema.Resources, error)
+}
+
+// expression returns the advanced query selecting the properties of the resource type
+func (q configQuery) expression() string {
+	fields := []string{"accountId", "awsRegion", "resourceId", "resourceName"}
+	for _, property := range q.properties {
+		fields = append(fields, "configuration."+property)
+	}
+	return fmt.Sprintf("SELECT %s WHERE resourceType = '%s'", strings.Join(fields, ", "), q.resourceType)
+}
+
+// configQueries are the resource types that can be listed from an
+// aggregator, the properties being named as in the config resource schema
+var configQueries = []configQuery{
+	{
+		services:     []string{"ec2", "instance"},
+		resourceType: "AWS::EC2::Instance",
+		properties:   []string{"publicIpAddress", "privateIpAddress"},
+		resources:    (*configAggregatorProvider).instanceResources,
+	},
+	{
+		services:     []string{"eip"},
+		resourceType: "AWS::EC2::EIP",
+		properties:   []string{"publicIp", "privateIpAddress", "allocationId", "associationId", "domain", "instanceId", "networkInterfaceId", "networkInterfaceOwnerId", "carrierIp"},
+		resources:    (*configAggregatorProvider).eipResources,
+	},
+	{
+		services:     []string{"alb"},
+		resourceType: "AWS::ElasticLoadBalancingV2::LoadBalancer",
+		properties:   []string{"dNSName"},
+		resources:    loadBalancerConfigResources("alb"),
+	},
+	{
+		services:     []string{"elb"},
+		resourceType: "AWS::ElasticLoadBalancing::LoadBalancer",
+		properties:   []string{"dnsname"},
+		resources:    loadBalancerConfigResources("elb"),
+	},
+	{
+		services:     []string{"s3"},
+		resourceType: "AWS::S3::Bucket",
+		resources:    (*configAggregatorProvider).bucketResources,
+	},
+	{
+		services:     []string{"cloudfront"},
+		resourceType: "AWS::CloudFront::Distribution",
+		properties:   []string{"domainName", "status", "distributionConfig.enabled", "distributionConfig.aliases", "distributionConfig.webACLId"},
+		resources:    (*configAggregatorProvider).distributionResources,
+	},
+	{
+		services:     []string{"rds"},
+		resourceType: "AWS::RDS::DBInstance",
+		properties:   []string{"endpoint", "publiclyAccessible", "dBClusterIdentifier", "engine"},
+		resources:    (*configAggregatorProvider).dbInstanceResources,
+	},
+	{
+		services:     []string{"rds"},
+		resourceType: "AWS::RDS::DBCluster",
+		properties:   []string{"endpoint", "readerEndpoint", "port", "publiclyAccessible", "engine"},
+		resources:    (*configAggregatorProvider).dbClusterResources,
+	},
+	{
+		services:     []string{"apigateway"},
+		resourceType: "AWS::ApiGateway::RestApi",
+		properties:   []string{"endpointConfiguration", "disableExecuteApiEndpoint"},
+		resources:    (*configAggregatorProvider).restAPIResources,
+	},
+	{
+		services:     []string{"apigateway"},
+		resourceType: "AWS::ApiGatewayV2::Api",
+		properties:   []string{"apiEndpoint", "protocolType", "disableExecuteApiEndpoint"},
+		resources:    (*configAggregatorProvider).httpAPIResources,
+	},
+}
+
+// configAggregatorProvider lists the resources of every account and region
+// of an aws config aggregator with advanced queries, without assuming a role
+// in each account
+type configAggregatorProvider struct {
+	options ProviderOptions
+	config  aws.Config
+	// publicClusters are the rds clusters with a publicly accessible
+	// instance, aurora clusters leaving their own flag unset
+	publicClusters map[string]bool
+}
+
+// GetResource returns the resources of the services that can be listed
+// from the aggregator, along with the enabled services that cannot.
+func (cp *configAggregatorProvider) GetResource(ctx context.Context) (*schema.Resources, schema.ServiceMap, error) {
+	list := schema.NewResources()
+	client := newClient(cp.config, cp.options.ConfigAggregatorRegion, configservice.NewFromConfig)
+
+	covered := make(schema.ServiceMap)
+	for _, query := range configQueries {
+		enabled := false
+		for _, service := range query.services {
+			if cp.options.Services.Has(service) {
+				covered[service] = struct{}{}
+				enabled = true
+			}
+		}
+		if !enabled {
+			continue
+		}
+
+		paginator := configservice.NewSelectAggregateResourceConfigPaginator(client, &configservice.SelectAggregateResourceConfigInput{
+			ConfigurationAggregatorName: aws.String(cp.options.ConfigAggregator),
+			Expression:                  aws.String(query.expression()),
+			MaxResults:                  100,
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "could not query %s resources of aggregator %s", query.resourceType, cp.options.ConfigAggregator)
+			}
+			for _, result := range page.Results {
+				item := &configItem{}
+				if err := json.Unmarshal([]byte(result), item); err != nil {
+					return nil, nil, errors.Wrapf(err, "could not decode %s configuration", query.resourceType)
+				}
+				resources, err := query.resources(cp, item)
+				if err != nil {
+					return nil, nil, errors.Wrapf(err, "could not decode %s configuration", query.resourceType)
+				}
+				list.Merge(resources)
+			}
+		}
+	}
+
+	remaining := make(schema.ServiceMap)
+	for service := range cp.options.Services {
+		if !covered.Has(service) {
+			remaining[service] = struct{}{}
+		}
+	}
+	return list, remaining, nil
+}
+
+// decode unmarshals the selected configuration properties of an item
+func (item *configItem) decode(v interface{}) error {
+	if len(item.Configuration) == 0 {
+		return nil
+	}
+	return json.Unmarshal(item.Configuration, v)
+}
+
+func (cp *configAggregatorProvider) instanceResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		PublicIPAddress  string `json:"publicIpAddress"`
+		PrivateIPAddress string `json:"privateIpAddress"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+
+	list := schema.NewResources()
+	if configuration.PrivateIPAddress != "" {
+		list.Append(&schema.Resource{
+			ID:          cp.options.Id,
+			Provider:    providerName,
+			PrivateIpv4: configuration.PrivateIPAddress,
+			Public:      false,
+			Service:     "instance",
+		})
+	}
+	list.Append(&schema.Resource{
+		ID:         cp.options.Id,
+		Provider:   providerName,
+		PublicIPv4: configuration.PublicIPAddress,
+		Public:     true,
+		Service:    "instance",
+	})
+	return list, nil
+}
+
+func (cp *configAggregatorProvider) eipResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		PublicIP                string `json:"publicIp"`
+		PrivateIPAddress        string `json:"privateIpAddress"`
+		AllocationID            string `json:"allocationId"`
+		AssociationID           string `json:"associationId"`
+		Domain                  string `json:"domain"`
+		InstanceID              string `json:"instanceId"`
+		NetworkInterfaceID      string `json:"networkInterfaceId"`
+		NetworkInterfaceOwnerID string `json:"networkInterfaceOwnerId"`
+		CarrierIP               string `json:"carrierIp"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+
+	// the owner of an interface is not part of the address configuration
+	ownerType := "unattached"
+	switch {
+	case configuration.NetworkInterfaceID != "":
+		ownerType = "interface"
+	case configuration.InstanceID != "":
+		ownerType = "instance"
+	}
+	accountID := configuration.NetworkInterfaceOwnerID
+	if accountID == "" {
+		accountID = item.AccountID
+	}
+	metadata := cleanMetadata(map[string]string{
+		"region":               item.AwsRegion,
+		"allocation_id":        configuration.AllocationID,
+		"domain":               configuration.Domain,
+		"owner_type":           ownerType,
+		"network_interface_id": configuration.NetworkInterfaceID,
+		"instance_id":          configuration.InstanceID,
+		"association_id":       configuration.AssociationID,
+		"account_id":           accountID,
+	})
+
+	list := schema.NewResources()
+	list.Append(&schema.Resource{
+		ID:          cp.options.Id,
+		Provider:    providerName,
+		Service:     "eip",
+		Public:      true,
+		PublicIPv4:  configuration.PublicIP,
+		PrivateIpv4: configuration.PrivateIPAddress,
+		Metadata:    metadata,
+	})
+	if configuration.CarrierIP != "" {
+		list.Append(&schema.Resource{
+			ID:         cp.options.Id,
+			Provider:   providerName,
+			Service:    "eip",
+			Public:     true,
+			PublicIPv4: configuration.CarrierIP,
+			Metadata:   metadata,
+		})
+	}
+	return list, nil
+}
+
+// loadBalancerConfigResources returns the dns name of a load balancer, both
+// generations being decoded since json field names match case-insensitively
+func loadBalancerConfigResources(service string) func(*configAggregatorProvider, *configItem) (*schema.Resources, error) {
+	return func(_ *configAggregatorProvider, item *configItem) (*schema.Resources, error) {
+		var configuration struct {
+			DNSName string `json:"dnsName"`
+		}
+		if err := item.decode(&configuration); err != nil {
+			return nil, err
+		}
+
+		list := schema.NewResources()
+		list.Append(&schema.Resource{
+			Provider: providerName,
+			ID:       item.ResourceName,
+			DNSName:  configuration.DNSName,
+			Public:   true,
+			Service:  service,
+		})
+		return list, nil
+	}
+}
+
+func (cp *configAggregatorProvider) bucketResources(item *configItem) (*schema.Resources, error) {
+	bucket, region := item.ResourceName, item.AwsRegion
+
+	list := schema.NewResources()
+	list.Append(&schema.Resource{
+		ID:       cp.options.Id,
+		Public:   true,
+		DNSName:  fmt.Sprintf("%s.s3.%s.%s", bucket, region, s3DomainSuffix(region)),
+		Provider: providerName,
+		Service:  "s3",
+		Metadata: map[string]string{
+			"region":        region,
+			"bucket":        bucket,
+			"endpoint_type": "virtual_hosted",
+		},
+	})
+	return list, nil
+}
+
+func (cp *configAggregatorProvider) distributionResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		DomainName         string `json:"domainName"`
+		Status             string `json:"status"`
+		DistributionConfig struct {
+			Enabled bool `json:"enabled"`
+			Aliases struct {
+				Items []string `json:"items"`
+			} `json:"aliases"`
+			WebACLID string `json:"webACLId"`
+		} `json:"distributionConfig"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+
+	metadata := cleanMetadata(map[string]string{
+		"distribution_id":     item.ResourceID,
+		"distribution_domain": configuration.DomainName,
+		"enabled":             strconv.FormatBool(configuration.DistributionConfig.Enabled),
+		"status":              configuration.Status,
+		"web_acl_id":          configuration.DistributionConfig.WebACLID,
+	})
+
+	list := schema.NewResources()
+	hosts := append([]string{configuration.DomainName}, configuration.DistributionConfig.Aliases.Items...)
+	for _, host := range hosts {
+		list.Append(&schema.Resource{
+			Provider: providerName,
+			ID:       item.ResourceID,
+			DNSName:  host,
+			Public:   true,
+			Service:  "cloudfront",
+			Metadata: metadata,
+		})
+	}
+	return list, nil
+}
+
+func (cp *configAggregatorProvider) dbInstanceResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		Endpoint struct {
+			Address string `json:"address"`
+			Port    int64  `json:"port"`
+		} `json:"endpoint"`
+		PubliclyAccessible  bool   `json:"publiclyAccessible"`
+		DBClusterIdentifier string `json:"dBClusterIdentifier"`
+		Engine              string `json:"engine"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+	// the instances are queried before the clusters which reuse their flag
+	if configuration.PubliclyAccessible && configuration.DBClusterIdentifier != "" {
+		if cp.publicClusters == nil {
+			cp.publicClusters = make(map[string]bool)
+		}
+		cp.publicClusters[clusterKey(item, configuration.DBClusterIdentifier)] = true
+	}
+
+	list := schema.NewResources()
+	if configuration.Endpoint.Address == "" {
+		return list, nil
+	}
+	list.Append(endpointResource(cp.options.Id, "rds", configuration.Endpoint.Address, configuration.Endpoint.Port, configuration.PubliclyAccessible, map[string]string{
+		"region":        item.AwsRegion,
+		"identifier":    item.ResourceName,
+		"cluster":       configuration.DBClusterIdentifier,
+		"engine":        configuration.Engine,
+		"endpoint_type": "instance",
+	}))
+	return list, nil
+}
+
+func (cp *configAggregatorProvider) dbClusterResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		Endpoint           string `json:"endpoint"`
+		ReaderEndpoint     string `json:"readerEndpoint"`
+		Port               int64  `json:"port"`
+		PubliclyAccessible bool   `json:"publiclyAccessible"`
+		Engine             string `json:"engine"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+
+	public := configuration.PubliclyAccessible || cp.publicClusters[clusterKey(item, item.ResourceName)]
+
+	list := schema.NewResources()
+	endpoints := map[string]string{
+		"writer": configuration.Endpoint,
+		"reader": configuration.ReaderEndpoint,
+	}
+	for endpointType, host := range endpoints {
+		if host == "" {
+			continue
+		}
+		list.Append(endpointResource(cp.options.Id, "rds", host, configuration.Port, public, map[string]string{
+			"region":        item.AwsRegion,
+			"cluster":       item.ResourceName,
+			"engine":        configuration.Engine,
+			"endpoint_type": endpointType,
+		}))
+	}
+	return list, nil
+}
+
+// clusterKey identifies an rds cluster across the accounts and regions of the aggregator
+func clusterKey(item *configItem, cluster string) string {
+	return item.AccountID + "/" + item.AwsRegion + "/" + cluster
+}
+
+func (cp *configAggregatorProvider) restAPIResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		EndpointConfiguration struct {
+			Types []string `json:"types"`
+		} `json:"endpointConfiguration"`
+		DisableExecuteAPIEndpoint bool `json:"disableExecuteApiEndpoint"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+
+	host := fmt.Sprintf("%s.execute-api.%s.amazonaws.com", item.ResourceID, item.AwsRegion)
+	metadata := map[string]string{
+		"region":        item.AwsRegion,
+		"api_id":        item.ResourceID,
+		"api_name":      item.ResourceName,
+		"api_type":      "rest",
+		"url":           "https://" + host,
+		"endpoint_type": strings.Join(configuration.EndpointConfiguration.Types, ","),
+	}
+	if configuration.DisableExecuteAPIEndpoint {
+		metadata["execute_api_endpoint_disabled"] = "true"
+	}
+
+	list := schema.NewResources()
+	list.Append(&schema.Resource{
+		ID:       cp.options.Id,
+		Provider: providerName,
+		DNSName:  host,
+		Public:   true,
+		Service:  "apigateway",
+		Metadata: cleanMetadata(metadata),
+	})
+	return list, nil
+}
+
+func (cp *configAggregatorProvider) httpAPIResources(item *configItem) (*schema.Resources, error) {
+	var configuration struct {
+		APIEndpoint               string `json:"apiEndpoint"`
+		ProtocolType              string `json:"protocolType"`
+		DisableExecuteAPIEndpoint bool   `json:"disableExecuteApiEndpoint"`
+	}
+	if err := item.decode(&configuration); err != nil {
+		return nil, err
+	}
+
+	list := schema.NewResources()
+	parsed, err := url.Parse(configuration.APIEndpoint)
+	if err != nil || parsed.Hostname() == "" {
+		return list, nil
+	}
+	metadata := map[string]string{
+		"region":   item.AwsRegion,
+		"api_id":   item.ResourceID,
+		"api_name": item.ResourceName,
+		"api_type": strings.ToLower(configuration.ProtocolType),
+		"url":      configuration.APIEndpoint,
+	}
+	if configuration.DisableExecuteAPIEndpoint {
+		metadata["execute_api_endpoint_disabled"] = "true"
+	}
+	list.Append(&schema.Resource{
+		ID:       cp.options.Id,
+		Provider: providerName,
+		DNSName:  parsed.Hostname(),
+		Public:   true,
+		Service:  "apigateway",
+		Metadata: cleanMetadata(metadata),
+	})
+	return list, nil
+}
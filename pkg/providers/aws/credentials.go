@@ -0,0 +1,57 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/gologger"
+)
+
+// loadConfig creates the base config for the provider.
+//
+// Static keys are used when configured. Otherwise the config is built from
+// the default credential chain of the sdk, which covers environment
+// variables, the shared config and credentials files (including the named
+// profile, SSO and credential_process entries), web identity tokens and
+// ECS/EC2 instance roles.
+func loadConfig(ctx context.Context, options *ProviderOptions) (aws.Config, error) {
+	optFns := []func(*config.LoadOptions) error{
+		config.WithRegion(globalRegion),
+		// allows profiles using mfa_serial to prompt for the token code
+		config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+			o.TokenProvider = stscreds.StdinTokenProvider
+		}),
+	}
+
+	switch {
+	case options.AccessKey != "" && options.SecretKey != "":
+		optFns = append(optFns, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(options.AccessKey, options.SecretKey, options.Token)))
+	case options.Profile != "":
+		gologger.Verbose().Msgf("Using aws profile %s", options.Profile)
+		optFns = append(optFns, config.WithSharedConfigProfile(options.Profile))
+	default:
+		gologger.Verbose().Msgf("No static aws keys configured, using default credential chain")
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return aws.Config{}, errors.Wrap(err, "could not load aws config")
+	}
+	return cfg, nil
+}
+
+// assumeRoleConfig returns a config using the credentials of the roles
+// configured with assume_role_arn, assumed in order with the base config.
+// The credentials are refreshed automatically before they expire.
+func assumeRoleConfig(ctx context.Context, cfg aws.Config, options *ProviderOptions) (aws.Config, error) {
+	assumed := assumeRoleChain(cfg, options.rootChain())
+	// fail early if the role cannot be assumed
+	if _, err := assumed.Credentials.Retrieve(ctx); err != nil {
+		return aws.Config{}, errors.Wrap(err, "failed to assume role")
+	}
+	return assumed, nil
+}
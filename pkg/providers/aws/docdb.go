@@ -0,0 +1,83 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/docdb"
+	"github.com/aws/aws-sdk-go-v2/service/docdb/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// docdbProvider is a provider for AWS DocumentDB cluster and instance endpoints
+type docdbProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (dp *docdbProvider) name() string {
+	return "docdb"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (dp *docdbProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, dp.configs, dp.regions, dp.listDocDBResources), nil
+}
+
+// the docdb api returns the rds and neptune clusters as well
+var docdbEngineFilter = []types.Filter{{
+	Name:   aws.String("engine"),
+	Values: []string{"docdb"},
+}}
+
+func (dp *docdbProvider) listDocDBResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	client := newClient(cfg, region, docdb.NewFromConfig)
+
+	clusters := docdb.NewDescribeDBClustersPaginator(client, &docdb.DescribeDBClustersInput{Filters: docdbEngineFilter})
+	for clusters.HasMorePages() {
+		page, err := clusters.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range page.DBClusters {
+			endpoints := map[string]string{
+				"writer": aws.ToString(cluster.Endpoint),
+				"reader": aws.ToString(cluster.ReaderEndpoint),
+			}
+			for endpointType, host := range endpoints {
+				if host == "" {
+					continue
+				}
+				list.Append(endpointResource(dp.options.Id, dp.name(), host, int64(aws.ToInt32(cluster.Port)), false, map[string]string{
+					"region":        region,
+					"cluster":       aws.ToString(cluster.DBClusterIdentifier),
+					"engine":        aws.ToString(cluster.Engine),
+					"endpoint_type": endpointType,
+				}))
+			}
+		}
+	}
+
+	instances := docdb.NewDescribeDBInstancesPaginator(client, &docdb.DescribeDBInstancesInput{Filters: docdbEngineFilter})
+	for instances.HasMorePages() {
+		page, err := instances.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, instance := range page.DBInstances {
+			if instance.Endpoint == nil || aws.ToString(instance.Endpoint.Address) == "" {
+				continue
+			}
+			list.Append(endpointResource(dp.options.Id, dp.name(), aws.ToString(instance.Endpoint.Address), int64(aws.ToInt32(instance.Endpoint.Port)), aws.ToBool(instance.PubliclyAccessible), map[string]string{
+				"region":        region,
+				"identifier":    aws.ToString(instance.DBInstanceIdentifier),
+				"cluster":       aws.ToString(instance.DBClusterIdentifier),
+				"engine":        aws.ToString(instance.Engine),
+				"endpoint_type": "instance",
+			}))
+		}
+	}
+	return list, nil
+}
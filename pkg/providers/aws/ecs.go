@@ -3,23 +3,26 @@ package aws
 import (
 	"context"
 	"fmt"
-	"sync"
+	"sort"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/ecs"
+	"github.com/aws/aws-sdk-go-v2/service/ecs/types"
+	"github.com/aws/aws-sdk-go-v2/service/servicediscovery"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 )
 
 // ecsProvider is a provider for aws ecs API
 type ecsProvider struct {
-	options   ProviderOptions
-	ecsClient *ecs.ECS
-	session   *session.Session
-	regions   *ec2.DescribeRegionsOutput
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
 }
 
 func (ep *ecsProvider) name() string {
@@ -28,182 +31,298 @@ func (ep *ecsProvider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (ep *ecsProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listECSResources), nil
+}
+
+// ecsClients are the clients used to resolve the tasks of a region
+type ecsClients struct {
+	ecs              *ecs.Client
+	ec2              *ec2.Client
+	serviceDiscovery *servicediscovery.Client
+	// cloudMapNames caches the dns names of cloud map services by arn
+	cloudMapNames map[string]string
+}
+
+func (ep *ecsProvider) listECSResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	clients := &ecsClients{
+		ecs:              newClient(cfg, region, ecs.NewFromConfig),
+		ec2:              newClient(cfg, region, ec2.NewFromConfig),
+		serviceDiscovery: newClient(cfg, region, servicediscovery.NewFromConfig),
+		cloudMapNames:    make(map[string]string),
+	}
+
+	var clusterArns []string
+	paginator := ecs.NewListClustersPaginator(clients.ecs, &ecs.ListClustersInput{MaxResults: aws.Int32(100)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		clusterArns = append(clusterArns, page.ClusterArns...)
+	}
+
 	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, region := range ep.regions.Regions {
-		ecsCleints, ec2Clients := ep.getEcsAndEc2Clients(region.RegionName)
-		for index := range len(ecsCleints) {
-			wg.Add(1)
-
-			go func(ecsClient *ecs.ECS, ec2Client *ec2.EC2) {
-				defer wg.Done()
-				if resources, err := ep.listECSResources(ecsClient, ec2Client); err == nil {
-					mu.Lock()
-					list.Merge(resources)
-					mu.Unlock()
-				}
-			}(ecsCleints[index], ec2Clients[index])
+	for _, clusterArn := range clusterArns {
+		resources, err := ep.listClusterTasks(ctx, clients, clusterArn, region)
+		if err != nil {
+			continue
 		}
+		list.Merge(resources)
 	}
-	wg.Wait()
 	return list, nil
 }
 
-func (ep *ecsProvider) listECSResources(ecsClient *ecs.ECS, ec2Client *ec2.EC2) (*schema.Resources, error) {
-	list := schema.NewResources()
-	req := &ecs.ListClustersInput{
-		MaxResults: aws.Int64(100),
+// listClusterTasks lists the addresses of the running tasks of a cluster,
+// standalone tasks included. awsvpc (and so fargate) tasks are resolved
+// through their network interface, other tasks through their ec2 instance.
+func (ep *ecsProvider) listClusterTasks(ctx context.Context, clients *ecsClients, clusterArn string, region string) (*schema.Resources, error) {
+	services := ep.describeServices(ctx, clients, clusterArn)
+
+	var taskArns []string
+	paginator := ecs.NewListTasksPaginator(clients.ecs, &ecs.ListTasksInput{Cluster: aws.String(clusterArn), MaxResults: aws.Int32(100)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list tasks")
+		}
+		taskArns = append(taskArns, page.TaskArns...)
 	}
-	for {
-		clustersOutput, err := ecsClient.ListClusters(req)
+
+	// tasks are described by batches of 100
+	var tasks []types.Task
+	for start := 0; start < len(taskArns); start += 100 {
+		end := min(start+100, len(taskArns))
+		output, err := clients.ecs.DescribeTasks(ctx, &ecs.DescribeTasksInput{Cluster: aws.String(clusterArn), Tasks: taskArns[start:end]})
 		if err != nil {
-			return nil, err
+			return nil, errors.Wrap(err, "could not describe tasks")
 		}
+		tasks = append(tasks, output.Tasks...)
+	}
 
-		for _, clusterArn := range clustersOutput.ClusterArns {
-			listServicesInputReq := &ecs.ListServicesInput{
-				Cluster:    clusterArn,
-				MaxResults: aws.Int64(100),
+	var interfaceIDs, containerInstanceArns []string
+	for _, task := range tasks {
+		if interfaceID := taskAttachmentDetail(&task, "networkInterfaceId"); interfaceID != "" {
+			interfaceIDs = append(interfaceIDs, interfaceID)
+		} else if task.ContainerInstanceArn != nil {
+			containerInstanceArns = append(containerInstanceArns, aws.ToString(task.ContainerInstanceArn))
+		}
+	}
+	// a failed lookup still reports the private address of the attachment
+	interfaces, _ := describeNetworkInterfaces(ctx, clients.ec2, interfaceIDs)
+	instances := ep.describeContainerInstances(ctx, clients, clusterArn, containerInstanceArns)
+
+	list := schema.NewResources()
+	for _, task := range tasks {
+		metadata := map[string]string{
+			"region":          region,
+			"cluster":         clusterArn,
+			"task_arn":        aws.ToString(task.TaskArn),
+			"task_definition": aws.ToString(task.TaskDefinitionArn),
+			"launch_type":     string(task.LaunchType),
+			"group":           aws.ToString(task.Group),
+		}
+		if serviceName, ok := strings.CutPrefix(aws.ToString(task.Group), "service:"); ok {
+			for key, value := range services[serviceName] {
+				metadata[key] = value
 			}
-			for {
-				servicesOutput, err := ecsClient.ListServices(listServicesInputReq)
-				if err != nil {
-					return nil, errors.Wrap(err, "could not list ECS services")
-				}
+		}
 
-				for _, serviceArn := range servicesOutput.ServiceArns {
-					listTasksInputReq := &ecs.ListTasksInput{
-						Cluster:     clusterArn,
-						ServiceName: serviceArn,
-						MaxResults:  aws.Int64(100),
-					}
-
-					for {
-						tasksOutput, err := ecsClient.ListTasks(listTasksInputReq)
-						if err != nil {
-							return nil, errors.Wrap(err, "could not list tasks")
-						}
-						if len(tasksOutput.TaskArns) == 0 {
-							break
-						}
-						describeTasksInput := &ecs.DescribeTasksInput{
-							Cluster: clusterArn,
-							Tasks:   tasksOutput.TaskArns,
-						}
-
-						describeTasksOutput, err := ecsClient.DescribeTasks(describeTasksInput)
-						if err != nil {
-							return nil, errors.Wrap(err, "could not describe tasks")
-						}
-
-						for _, task := range describeTasksOutput.Tasks {
-							if task.ContainerInstanceArn == nil {
-								continue
-							}
-							describeContainerInstancesInput := &ecs.DescribeContainerInstancesInput{
-								Cluster:            clusterArn,
-								ContainerInstances: []*string{task.ContainerInstanceArn},
-							}
-
-							describeContainerInstancesOutput, err := ecsClient.DescribeContainerInstances(describeContainerInstancesInput)
-							if err != nil {
-								return nil, errors.Wrap(err, "could not describe container instances")
-							}
-
-							for _, containerInstance := range describeContainerInstancesOutput.ContainerInstances {
-								instanceID := containerInstance.Ec2InstanceId
-								describeInstancesInput := &ec2.DescribeInstancesInput{
-									InstanceIds: []*string{instanceID},
-								}
-
-								describeInstancesOutput, err := ec2Client.DescribeInstances(describeInstancesInput)
-								if err != nil {
-									continue
-								}
-
-								for _, reservation := range describeInstancesOutput.Reservations {
-									for _, instance := range reservation.Instances {
-										ip4 := aws.StringValue(instance.PublicIpAddress)
-										ip6 := aws.StringValue(instance.Ipv6Address)
-										privateIp4 := aws.StringValue(instance.PrivateIpAddress)
-
-										if privateIp4 != "" {
-											resource := &schema.Resource{
-												ID:          aws.StringValue(instance.InstanceId),
-												Provider:    "aws",
-												PrivateIpv4: privateIp4,
-												Public:      false,
-												Service:     ep.name(),
-											}
-											list.Append(resource)
-										}
-
-										if ip4 != "" || ip6 != "" {
-											resource := &schema.Resource{
-												ID:         aws.StringValue(instance.InstanceId),
-												Provider:   "aws",
-												PublicIPv4: ip4,
-												PublicIPv6: ip6,
-												Public:     true,
-												Service:    ep.name(),
-											}
-											list.Append(resource)
-										}
-									}
-								}
-							}
-						}
-
-						if aws.StringValue(listTasksInputReq.NextToken) == "" {
-							break
-						}
-						listTasksInputReq.SetNextToken(*listTasksInputReq.NextToken)
-					}
+		if interfaceID := taskAttachmentDetail(&task, "networkInterfaceId"); interfaceID != "" {
+			metadata["network_interface_id"] = interfaceID
+			resource := &schema.Resource{
+				ID:          ep.options.Id,
+				Provider:    providerName,
+				PrivateIpv4: taskAttachmentDetail(&task, "privateIPv4Address"),
+				Service:     ep.name(),
+				Metadata:    cleanMetadata(metadata),
+			}
+			if eni, ok := interfaces[interfaceID]; ok {
+				resource.PrivateIpv4 = aws.ToString(eni.PrivateIpAddress)
+				if eni.Association != nil {
+					resource.PublicIPv4 = aws.ToString(eni.Association.PublicIp)
 				}
-
-				if aws.StringValue(servicesOutput.NextToken) == "" {
-					break
+				if len(eni.Ipv6Addresses) > 0 {
+					resource.PublicIPv6 = aws.ToString(eni.Ipv6Addresses[0].Ipv6Address)
 				}
-				listServicesInputReq.SetNextToken(*servicesOutput.NextToken)
 			}
+			resource.Public = resource.PublicIPv4 != "" || resource.PublicIPv6 != ""
+			list.Append(resource)
+			continue
 		}
-		if aws.StringValue(clustersOutput.NextToken) == "" {
-			break
+
+		instance, ok := instances[aws.ToString(task.ContainerInstanceArn)]
+		if !ok {
+			continue
 		}
-		req.SetNextToken(*clustersOutput.NextToken)
+		metadata["instance_id"] = aws.ToString(instance.InstanceId)
+		ip4 := aws.ToString(instance.PublicIpAddress)
+		ip6 := aws.ToString(instance.Ipv6Address)
+		list.Append(&schema.Resource{
+			ID:          aws.ToString(instance.InstanceId),
+			Provider:    providerName,
+			PrivateIpv4: aws.ToString(instance.PrivateIpAddress),
+			PublicIPv4:  ip4,
+			PublicIPv6:  ip6,
+			Public:      ip4 != "" || ip6 != "",
+			Service:     ep.name(),
+			Metadata:    cleanMetadata(metadata),
+		})
 	}
 	return list, nil
 }
 
-func (ep *ecsProvider) getEcsAndEc2Clients(region *string) ([]*ecs.ECS, []*ec2.EC2) {
-	ecsClients := make([]*ecs.ECS, 0)
-	ec2Clients := make([]*ec2.EC2, 0)
+// describeServices returns the load balancer and service discovery
+// metadata of the services of a cluster by service name
+func (ep *ecsProvider) describeServices(ctx context.Context, clients *ecsClients, clusterArn string) map[string]map[string]string {
+	services := make(map[string]map[string]string)
 
-	albClient := ecs.New(ep.session, aws.NewConfig().WithRegion(*region))
-	ecsClients = append(ecsClients, albClient)
+	var serviceArns []string
+	paginator := ecs.NewListServicesPaginator(clients.ecs, &ecs.ListServicesInput{Cluster: aws.String(clusterArn), MaxResults: aws.Int32(100)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return services
+		}
+		serviceArns = append(serviceArns, page.ServiceArns...)
+	}
 
-	ec2Client := ec2.New(ep.session, aws.NewConfig().WithRegion(*region))
-	ec2Clients = append(ec2Clients, ec2Client)
+	// services are described by batches of 10
+	for start := 0; start < len(serviceArns); start += 10 {
+		end := min(start+10, len(serviceArns))
+		output, err := clients.ecs.DescribeServices(ctx, &ecs.DescribeServicesInput{Cluster: aws.String(clusterArn), Services: serviceArns[start:end]})
+		if err != nil {
+			continue
+		}
+		for _, service := range output.Services {
+			var loadBalancers []string
+			for _, lb := range service.LoadBalancers {
+				if name := aws.ToString(lb.LoadBalancerName); name != "" {
+					loadBalancers = append(loadBalancers, name)
+				} else {
+					loadBalancers = append(loadBalancers, aws.ToString(lb.TargetGroupArn))
+				}
+			}
+
+			var cloudMapNames []string
+			for _, registry := range service.ServiceRegistries {
+				if name := clients.cloudMapName(ctx, aws.ToString(registry.RegistryArn)); name != "" {
+					cloudMapNames = append(cloudMapNames, name)
+				}
+			}
 
-	if ep.options.AssumeRoleName == "" || len(ep.options.AccountIds) < 1 {
-		return ecsClients, ec2Clients
+			services[aws.ToString(service.ServiceName)] = map[string]string{
+				"service":         aws.ToString(service.ServiceName),
+				"load_balancers":  strings.Join(loadBalancers, ","),
+				"cloud_map_names": strings.Join(cloudMapNames, ","),
+				"service_connect": strings.Join(serviceConnectNames(&service), ","),
+			}
+		}
 	}
+	return services
+}
 
-	for _, accountId := range ep.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, ep.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(ep.session, roleARN)
+// describeContainerInstances returns the ec2 instances of container instances by arn
+func (ep *ecsProvider) describeContainerInstances(ctx context.Context, clients *ecsClients, clusterArn string, containerInstanceArns []string) map[string]ec2types.Instance {
+	instances := make(map[string]ec2types.Instance)
+	containerInstanceArns = sliceutil.Dedupe(containerInstanceArns)
 
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      region,
-			Credentials: creds,
+	// container instances are described by batches of 100
+	instanceIDs := make(map[string][]string)
+	for start := 0; start < len(containerInstanceArns); start += 100 {
+		end := min(start+100, len(containerInstanceArns))
+		output, err := clients.ecs.DescribeContainerInstances(ctx, &ecs.DescribeContainerInstancesInput{
+			Cluster:            aws.String(clusterArn),
+			ContainerInstances: containerInstanceArns[start:end],
 		})
 		if err != nil {
 			continue
 		}
-		ecsClients = append(ecsClients, ecs.New(assumeSession))
-		ec2Clients = append(ec2Clients, ec2.New(assumeSession))
+		for _, containerInstance := range output.ContainerInstances {
+			instanceID := aws.ToString(containerInstance.Ec2InstanceId)
+			instanceIDs[instanceID] = append(instanceIDs[instanceID], aws.ToString(containerInstance.ContainerInstanceArn))
+		}
+	}
+
+	ids := make([]string, 0, len(instanceIDs))
+	for id := range instanceIDs {
+		ids = append(ids, id)
+	}
+	// a failed batch still reports the instances of the other batches
+	described, _ := describeInstances(ctx, clients.ec2, ids)
+	for id, instance := range described {
+		for _, containerInstanceArn := range instanceIDs[id] {
+			instances[containerInstanceArn] = instance
+		}
+	}
+	return instances
+}
+
+// cloudMapName returns the <service>.<namespace> dns name of a cloud map service
+func (c *ecsClients) cloudMapName(ctx context.Context, registryArn string) string {
+	if name, ok := c.cloudMapNames[registryArn]; ok {
+		return name
+	}
+	c.cloudMapNames[registryArn] = ""
+
+	parsed, err := arn.Parse(registryArn)
+	if err != nil {
+		return ""
+	}
+	serviceID := strings.TrimPrefix(parsed.Resource, "service/")
+	service, err := c.serviceDiscovery.GetService(ctx, &servicediscovery.GetServiceInput{Id: aws.String(serviceID)})
+	if err != nil || service.Service == nil {
+		return ""
+	}
+	namespace, err := c.serviceDiscovery.GetNamespace(ctx, &servicediscovery.GetNamespaceInput{Id: service.Service.NamespaceId})
+	if err != nil || namespace.Namespace == nil {
+		return ""
+	}
+	name := fmt.Sprintf("%s.%s", aws.ToString(service.Service.Name), aws.ToString(namespace.Namespace.Name))
+	c.cloudMapNames[registryArn] = name
+	return name
+}
+
+// serviceConnectNames returns the client aliases of the service connect
+// configuration of the deployments of a service
+func serviceConnectNames(service *types.Service) []string {
+	names := make(map[string]struct{})
+	for _, deployment := range service.Deployments {
+		config := deployment.ServiceConnectConfiguration
+		if config == nil || !config.Enabled {
+			continue
+		}
+		for _, connectService := range config.Services {
+			for _, alias := range connectService.ClientAliases {
+				name := aws.ToString(alias.DnsName)
+				if name == "" {
+					name = aws.ToString(connectService.DiscoveryName)
+				}
+				if name == "" {
+					name = aws.ToString(connectService.PortName)
+				}
+				names[fmt.Sprintf("%s:%d", name, aws.ToInt32(alias.Port))] = struct{}{}
+			}
+		}
+	}
+	result := make([]string, 0, len(names))
+	for name := range names {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// taskAttachmentDetail returns a detail of the elastic network interface attachment of a task
+func taskAttachmentDetail(task *types.Task, name string) string {
+	for _, attachment := range task.Attachments {
+		if aws.ToString(attachment.Type) != "ElasticNetworkInterface" {
+			continue
+		}
+		for _, detail := range attachment.Details {
+			if aws.ToString(detail.Name) == name {
+				return aws.ToString(detail.Value)
+			}
+		}
 	}
-	return ecsClients, ec2Clients
+	return ""
 }
@@ -0,0 +1,134 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// eipProvider is a provider for aws elastic ip addresses, including
+// unattached addresses and addresses of nat gateways and load balancers.
+type eipProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ep *eipProvider) name() string {
+	return "eip"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ep *eipProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listAddresses), nil
+}
+
+func (ep *eipProvider) listAddresses(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	ec2Client := newClient(cfg, region, ec2.NewFromConfig)
+	output, err := ec2Client.DescribeAddresses(ctx, &ec2.DescribeAddressesInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaceIDs []string
+	for _, address := range output.Addresses {
+		if id := aws.ToString(address.NetworkInterfaceId); id != "" {
+			interfaceIDs = append(interfaceIDs, id)
+		}
+	}
+	// owner types are only known from the attached interfaces, a failed
+	// lookup still reports the addresses without it.
+	owners, _ := describeInterfaceOwners(ctx, ec2Client, interfaceIDs)
+
+	for _, address := range output.Addresses {
+		metadata := map[string]string{
+			"region":        region,
+			"allocation_id": aws.ToString(address.AllocationId),
+			"domain":        string(address.Domain),
+		}
+		switch {
+		case aws.ToString(address.NetworkInterfaceId) != "":
+			metadata["network_interface_id"] = aws.ToString(address.NetworkInterfaceId)
+			metadata["owner_type"] = owners[aws.ToString(address.NetworkInterfaceId)]
+			if metadata["owner_type"] == "" {
+				metadata["owner_type"] = "interface"
+			}
+		case aws.ToString(address.InstanceId) != "":
+			metadata["owner_type"] = "instance"
+		default:
+			metadata["owner_type"] = "unattached"
+		}
+		if id := aws.ToString(address.InstanceId); id != "" {
+			metadata["instance_id"] = id
+		}
+		if id := aws.ToString(address.AssociationId); id != "" {
+			metadata["association_id"] = id
+		}
+		if id := aws.ToString(address.NetworkInterfaceOwnerId); id != "" {
+			metadata["account_id"] = id
+		}
+
+		list.Append(&schema.Resource{
+			ID:          ep.options.Id,
+			Provider:    providerName,
+			Service:     ep.name(),
+			Public:      true,
+			PublicIPv4:  aws.ToString(address.PublicIp),
+			PrivateIpv4: aws.ToString(address.PrivateIpAddress),
+			Metadata:    metadata,
+		})
+		// carrier ips are the public addresses of wavelength zones
+		if carrierIP := aws.ToString(address.CarrierIp); carrierIP != "" {
+			list.Append(&schema.Resource{
+				ID:         ep.options.Id,
+				Provider:   providerName,
+				Service:    ep.name(),
+				Public:     true,
+				PublicIPv4: carrierIP,
+				Metadata:   metadata,
+			})
+		}
+	}
+	return list, nil
+}
+
+// describeInterfaceOwners returns the owner type of the network interfaces by id
+func describeInterfaceOwners(ctx context.Context, ec2Client *ec2.Client, interfaceIDs []string) (map[string]string, error) {
+	interfaces, err := describeNetworkInterfaces(ctx, ec2Client, interfaceIDs)
+	owners := make(map[string]string, len(interfaces))
+	for id, eni := range interfaces {
+		owners[id] = networkInterfaceOwnerType(&eni)
+	}
+	return owners, err
+}
+
+// describeNetworkInterfaces returns the network interfaces by id
+func describeNetworkInterfaces(ctx context.Context, ec2Client *ec2.Client, interfaceIDs []string) (map[string]types.NetworkInterface, error) {
+	interfaces := make(map[string]types.NetworkInterface)
+	// the filter accepts a limited number of values per request
+	const chunkSize = 200
+	for start := 0; start < len(interfaceIDs); start += chunkSize {
+		end := min(start+chunkSize, len(interfaceIDs))
+		req := &ec2.DescribeNetworkInterfacesInput{
+			Filters: []types.Filter{{
+				Name:   aws.String("network-interface-id"),
+				Values: interfaceIDs[start:end],
+			}},
+		}
+		paginator := ec2.NewDescribeNetworkInterfacesPaginator(ec2Client, req)
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return interfaces, err
+			}
+			for _, eni := range page.NetworkInterfaces {
+				interfaces[aws.ToString(eni.NetworkInterfaceId)] = eni
+			}
+		}
+	}
+	return interfaces, nil
+}
@@ -4,27 +4,24 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/eks"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
-	"sigs.k8s.io/aws-iam-authenticator/pkg/token"
 )
 
 // eksProvider is a provider for AWS EKS API.
 type eksProvider struct {
-	options   ProviderOptions
-	eksClient *eks.EKS
-	session   *session.Session
-	regions   *ec2.DescribeRegionsOutput
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
 }
 
 func (ep *eksProvider) name() string {
@@ -33,59 +30,43 @@ func (ep *eksProvider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (ep *eksProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	for _, region := range ep.regions.Regions {
-		for _, eksClient := range ep.getEksClients(region.RegionName) {
-			wg.Add(1)
-
-			go func(client *eks.EKS) {
-				defer wg.Done()
-				if resources, err := ep.listEKSResources(client); err == nil {
-					mu.Lock()
-					list.Merge(resources)
-					mu.Unlock()
-				}
-			}(eksClient)
-		}
-	}
-	wg.Wait()
-	return list, nil
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listEKSResources), nil
 }
 
-func (ep *eksProvider) listEKSResources(eksClient *eks.EKS) (*schema.Resources, error) {
+func (ep *eksProvider) listEKSResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
 	list := schema.NewResources()
-	req := &eks.ListClustersInput{
-		MaxResults: aws.Int64(100),
-	}
-	for {
-		clustersOutput, err := eksClient.ListClusters(req)
+	eksClient := newClient(cfg, region, eks.NewFromConfig)
+
+	paginator := eks.NewListClustersPaginator(eksClient, &eks.ListClustersInput{
+		MaxResults: aws.Int32(100),
+	})
+	for paginator.HasMorePages() {
+		clustersOutput, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not list EKS clusters")
 		}
 		// Iterate over each cluster
 		for _, clusterName := range clustersOutput.Clusters {
 			// describe cluster
-			clusterOutput, err := eksClient.DescribeCluster(&eks.DescribeClusterInput{
-				Name: clusterName,
+			clusterOutput, err := eksClient.DescribeCluster(ctx, &eks.DescribeClusterInput{
+				Name: aws.String(clusterName),
 			})
 			if err != nil {
-				return nil, errors.Wrapf(err, "could not describe EKS cluster: %s", *clusterName)
+				return nil, errors.Wrapf(err, "could not describe EKS cluster: %s", clusterName)
 			}
-			clientset, err := newClientset(clusterOutput.Cluster)
+			clientset, err := newClientset(ctx, newClient(cfg, region, sts.NewFromConfig), clusterOutput.Cluster)
 			if err != nil {
-				return nil, errors.Wrapf(err, "could not create clientset for EKS cluster: %s", *clusterName)
+				return nil, errors.Wrapf(err, "could not create clientset for EKS cluster: %s", clusterName)
 			}
-			nodes, err := clientset.CoreV1().Nodes().List(context.Background(), metav1.ListOptions{})
+			nodes, err := clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
 			if err != nil {
-				return nil, errors.Wrapf(err, "could not list nodes for EKS cluster: %s", *clusterName)
+				return nil, errors.Wrapf(err, "could not list nodes for EKS cluster: %s", clusterName)
 			}
 			// Iterate over each node
 			for _, node := range nodes.Items {
 				var podIPs []string
 				// List IP addresses of pods running on the node
-				pods, err := clientset.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{
+				pods, err := clientset.CoreV1().Pods("").List(ctx, metav1.ListOptions{
 					FieldSelector: fmt.Sprintf("spec.nodeName=%s", node.GetName()),
 				})
 				if err != nil {
@@ -118,64 +99,23 @@ func (ep *eksProvider) listEKSResources(eksClient *eks.EKS) (*schema.Resources,
 				}
 			}
 		}
-		if aws.StringValue(clustersOutput.NextToken) == "" {
-			break
-		}
-		req.SetNextToken(*clustersOutput.NextToken)
 	}
 	return list, nil
 }
 
-func (ep *eksProvider) getEksClients(region *string) []*eks.EKS {
-	eksClients := make([]*eks.EKS, 0)
-
-	eksClient := eks.New(
-		ep.session,
-		aws.NewConfig().WithRegion(aws.StringValue(region)),
-	)
-	eksClients = append(eksClients, eksClient)
-
-	if ep.options.AssumeRoleName == "" || len(ep.options.AccountIds) < 1 {
-		return eksClients
-	}
-
-	for _, accountId := range ep.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, ep.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(ep.session, roleARN)
-
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      region,
-			Credentials: creds,
-		})
-		if err != nil {
-			continue
-		}
-
-		eksClients = append(eksClients, eks.New(assumeSession))
-	}
-	return eksClients
-}
-
-func newClientset(cluster *eks.Cluster) (*kubernetes.Clientset, error) {
-	gen, err := token.NewGenerator(true, false)
+func newClientset(ctx context.Context, stsClient *sts.Client, cluster *types.Cluster) (*kubernetes.Clientset, error) {
+	tok, err := eksToken(ctx, stsClient, aws.ToString(cluster.Name))
 	if err != nil {
 		return nil, err
 	}
-	opts := &token.GetTokenOptions{
-		ClusterID: aws.StringValue(cluster.Name),
-	}
-	tok, err := gen.GetWithOptions(opts)
-	if err != nil {
-		return nil, err
-	}
-	ca, err := base64.StdEncoding.DecodeString(aws.StringValue(cluster.CertificateAuthority.Data))
+	ca, err := base64.StdEncoding.DecodeString(aws.ToString(cluster.CertificateAuthority.Data))
 	if err != nil {
 		return nil, err
 	}
 	clientset, err := kubernetes.NewForConfig(
 		&rest.Config{
-			Host:        aws.StringValue(cluster.Endpoint),
-			BearerToken: tok.Token,
+			Host:        aws.ToString(cluster.Endpoint),
+			BearerToken: tok,
 			TLSClientConfig: rest.TLSClientConfig{
 				CAData: ca,
 			},
@@ -186,3 +126,22 @@ func newClientset(cluster *eks.Cluster) (*kubernetes.Clientset, error) {
 	}
 	return clientset, nil
 }
+
+// eksToken returns a bearer token for the cluster, which is a presigned
+// sts GetCallerIdentity url bound to the cluster name (the same token
+// aws eks get-token and aws-iam-authenticator generate).
+func eksToken(ctx context.Context, stsClient *sts.Client, clusterName string) (string, error) {
+	presigner := sts.NewPresignClient(stsClient)
+	request, err := presigner.PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}, func(o *sts.PresignOptions) {
+		o.ClientOptions = append(o.ClientOptions, func(o *sts.Options) {
+			o.APIOptions = append(o.APIOptions,
+				smithyhttp.SetHeaderValue("x-k8s-aws-id", clusterName),
+				smithyhttp.SetHeaderValue("X-Amz-Expires", "60"),
+			)
+		})
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "could not presign token request")
+	}
+	return "k8s-aws-v1." + base64.RawURLEncoding.EncodeToString([]byte(request.URL)), nil
+}
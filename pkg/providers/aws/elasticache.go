@@ -0,0 +1,79 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// elasticacheProvider is a provider for AWS ElastiCache (redis, valkey and memcached) endpoints.
+//
+// ElastiCache endpoints only resolve to addresses inside their vpc,
+// so they are never reported as publicly accessible.
+type elasticacheProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ep *elasticacheProvider) name() string {
+	return "elasticache"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ep *elasticacheProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listElastiCacheResources), nil
+}
+
+func (ep *elasticacheProvider) listElastiCacheResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	client := newClient(cfg, region, elasticache.NewFromConfig)
+
+	appendEndpoint := func(endpoint *types.Endpoint, identifier, engine, endpointType string) {
+		if endpoint == nil || aws.ToString(endpoint.Address) == "" {
+			return
+		}
+		list.Append(endpointResource(ep.options.Id, ep.name(), aws.ToString(endpoint.Address), int64(aws.ToInt32(endpoint.Port)), false, map[string]string{
+			"region":        region,
+			"identifier":    identifier,
+			"engine":        engine,
+			"endpoint_type": endpointType,
+		}))
+	}
+
+	groups := elasticache.NewDescribeReplicationGroupsPaginator(client, &elasticache.DescribeReplicationGroupsInput{})
+	for groups.HasMorePages() {
+		page, err := groups.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, group := range page.ReplicationGroups {
+			identifier := aws.ToString(group.ReplicationGroupId)
+			engine := aws.ToString(group.Engine)
+			appendEndpoint(group.ConfigurationEndpoint, identifier, engine, "configuration")
+			for _, nodeGroup := range group.NodeGroups {
+				appendEndpoint(nodeGroup.PrimaryEndpoint, identifier, engine, "primary")
+				appendEndpoint(nodeGroup.ReaderEndpoint, identifier, engine, "reader")
+			}
+		}
+	}
+
+	clusters := elasticache.NewDescribeCacheClustersPaginator(client, &elasticache.DescribeCacheClustersInput{ShowCacheNodeInfo: aws.Bool(true)})
+	for clusters.HasMorePages() {
+		page, err := clusters.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, cluster := range page.CacheClusters {
+			identifier := aws.ToString(cluster.CacheClusterId)
+			appendEndpoint(cluster.ConfigurationEndpoint, identifier, aws.ToString(cluster.Engine), "configuration")
+			for _, node := range cluster.CacheNodes {
+				appendEndpoint(node.Endpoint, identifier, aws.ToString(cluster.Engine), "node")
+			}
+		}
+	}
+	return list, nil
+}
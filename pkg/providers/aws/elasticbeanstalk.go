@@ -0,0 +1,82 @@
+package aws
+
+import (
+	"context"
+	"net"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/elasticbeanstalk"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// elasticBeanstalkProvider is a provider for AWS Elastic Beanstalk environments
+type elasticBeanstalkProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ep *elasticBeanstalkProvider) name() string {
+	return "elasticbeanstalk"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ep *elasticBeanstalkProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listEnvironments), nil
+}
+
+func (ep *elasticBeanstalkProvider) listEnvironments(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	client := newClient(cfg, region, elasticbeanstalk.NewFromConfig)
+
+	req := &elasticbeanstalk.DescribeEnvironmentsInput{IncludeDeleted: aws.Bool(false)}
+	for {
+		resp, err := client.DescribeEnvironments(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		for _, environment := range resp.Environments {
+			metadata := cleanMetadata(map[string]string{
+				"region":      region,
+				"application": aws.ToString(environment.ApplicationName),
+				"environment": aws.ToString(environment.EnvironmentName),
+				"cname":       aws.ToString(environment.CNAME),
+				"status":      string(environment.Status),
+				"health":      string(environment.Health),
+			})
+			if environment.Tier != nil {
+				metadata["tier"] = aws.ToString(environment.Tier.Name)
+			}
+
+			// the endpoint is the load balancer of the environment, or the
+			// elastic ip of its instance for single instance environments
+			resource := &schema.Resource{
+				ID:       ep.options.Id,
+				Provider: providerName,
+				DNSName:  aws.ToString(environment.CNAME),
+				Public:   true,
+				Service:  ep.name(),
+				Metadata: metadata,
+			}
+			endpoint := aws.ToString(environment.EndpointURL)
+			if ip := net.ParseIP(endpoint); ip != nil && ip.To4() != nil {
+				resource.PublicIPv4 = endpoint
+			} else if endpoint != "" && endpoint != resource.DNSName {
+				list.Append(&schema.Resource{
+					ID:       ep.options.Id,
+					Provider: providerName,
+					DNSName:  endpoint,
+					Public:   true,
+					Service:  ep.name(),
+					Metadata: metadata,
+				})
+			}
+			list.Append(resource)
+		}
+		if aws.ToString(resp.NextToken) == "" {
+			break
+		}
+		req.NextToken = resp.NextToken
+	}
+	return list, nil
+}
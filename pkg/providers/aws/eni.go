@@ -0,0 +1,139 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// eniProvider is a provider for aws elastic network interfaces, including
+// the interfaces owned by managed services (rds, lambda, vpc endpoints, etc).
+type eniProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (ep *eniProvider) name() string {
+	return "eni"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (ep *eniProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, ep.configs, ep.regions, ep.listNetworkInterfaces), nil
+}
+
+func (ep *eniProvider) listNetworkInterfaces(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	ec2Client := newClient(cfg, region, ec2.NewFromConfig)
+
+	paginator := ec2.NewDescribeNetworkInterfacesPaginator(ec2Client, &ec2.DescribeNetworkInterfacesInput{MaxResults: aws.Int32(1000)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, eni := range page.NetworkInterfaces {
+			metadata := networkInterfaceMetadata(&eni, region)
+
+			for _, address := range eni.PrivateIpAddresses {
+				resource := &schema.Resource{
+					ID:          ep.options.Id,
+					Provider:    providerName,
+					Service:     ep.name(),
+					PrivateIpv4: aws.ToString(address.PrivateIpAddress),
+					Metadata:    metadata,
+				}
+				if address.Association != nil {
+					resource.PublicIPv4 = aws.ToString(address.Association.PublicIp)
+					resource.DNSName = aws.ToString(address.Association.PublicDnsName)
+					resource.Public = resource.PublicIPv4 != ""
+				}
+				list.Append(resource)
+			}
+			for _, address := range eni.Ipv6Addresses {
+				list.Append(&schema.Resource{
+					ID:         ep.options.Id,
+					Provider:   providerName,
+					Service:    ep.name(),
+					PublicIPv6: aws.ToString(address.Ipv6Address),
+					Public:     true,
+					Metadata:   metadata,
+				})
+			}
+		}
+	}
+	return list, nil
+}
+
+// networkInterfaceMetadata returns the metadata describing the interface and its owner
+func networkInterfaceMetadata(eni *types.NetworkInterface, region string) map[string]string {
+	metadata := map[string]string{
+		"region":               region,
+		"network_interface_id": aws.ToString(eni.NetworkInterfaceId),
+		"interface_type":       string(eni.InterfaceType),
+		"owner_type":           networkInterfaceOwnerType(eni),
+		"account_id":           aws.ToString(eni.OwnerId),
+		"vpc_id":               aws.ToString(eni.VpcId),
+		"description":          aws.ToString(eni.Description),
+	}
+	if eni.Attachment != nil && aws.ToString(eni.Attachment.InstanceId) != "" {
+		metadata["instance_id"] = aws.ToString(eni.Attachment.InstanceId)
+	}
+	if requester := aws.ToString(eni.RequesterId); requester != "" {
+		metadata["requester_id"] = requester
+	}
+	return cleanMetadata(metadata)
+}
+
+// managedInterfaceOwners maps the description prefix of requester managed
+// interfaces to the type of the owning resource.
+var managedInterfaceOwners = []struct {
+	prefix string
+	owner  string
+}{
+	{"RDSNetworkInterface", "rds"},
+	{"AWS Lambda VPC ENI", "lambda"},
+	{"AWS created network interface for directory", "directory_service"},
+	{"ElastiCache", "elasticache"},
+	{"ELB app/", "alb"},
+	{"ELB net/", "nlb"},
+	{"ELB ", "elb"},
+	{"VPC Endpoint Interface", "vpc_endpoint"},
+	{"Interface for NAT Gateway", "nat_gateway"},
+	{"arn:aws:ecs:", "ecs_task"},
+	{"Amazon EKS", "eks"},
+	{"EFS mount target", "efs"},
+	{"DMSNetworkInterface", "dms"},
+	{"Network interface for Transit Gateway Attachment", "transit_gateway"},
+	{"Amazon Redshift", "redshift"},
+	{"Amazon OpenSearch", "opensearch"},
+	{"ES ", "opensearch"},
+}
+
+// networkInterfaceOwnerType returns the type of the resource owning the interface
+func networkInterfaceOwnerType(eni *types.NetworkInterface) string {
+	if interfaceType := eni.InterfaceType; interfaceType != "" && interfaceType != types.NetworkInterfaceTypeInterface {
+		return string(interfaceType)
+	}
+	description := aws.ToString(eni.Description)
+	for _, item := range managedInterfaceOwners {
+		if strings.HasPrefix(description, item.prefix) {
+			return item.owner
+		}
+	}
+	if eni.Attachment != nil && aws.ToString(eni.Attachment.InstanceId) != "" {
+		return "instance"
+	}
+	if aws.ToBool(eni.RequesterManaged) {
+		return "managed"
+	}
+	if eni.Attachment == nil {
+		return "unattached"
+	}
+	return "interface"
+}
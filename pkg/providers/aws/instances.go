@@ -2,22 +2,18 @@ package aws
 
 import (
 	"context"
-	"fmt"
-	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
 
 // awsInstanceProvider is an instance provider for aws API
 type instanceProvider struct {
-	options   ProviderOptions
-	ec2Client *ec2.EC2
-	session   *session.Session
-	regions   *ec2.DescribeRegionsOutput
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
 }
 
 func (d *instanceProvider) name() string {
@@ -26,46 +22,27 @@ func (d *instanceProvider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (i *instanceProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, region := range i.regions.Regions {
-		for _, ec2Client := range i.getEc2Clients(region.RegionName) {
-			wg.Add(1)
-
-			go func(ec2Client *ec2.EC2) {
-				defer wg.Done()
-
-				if resources, err := i.getEC2Resources(ec2Client); err == nil {
-					mu.Lock()
-					list.Merge(resources)
-					mu.Unlock()
-				}
-			}(ec2Client)
-		}
-	}
-	wg.Wait()
-	return list, nil
+	return listAllRegions(ctx, i.configs, i.regions, i.getEC2Resources), nil
 }
 
-func (i *instanceProvider) getEC2Resources(ec2Client *ec2.EC2) (*schema.Resources, error) {
+func (i *instanceProvider) getEC2Resources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
 	list := schema.NewResources()
+	ec2Client := newClient(cfg, region, ec2.NewFromConfig)
 
-	req := &ec2.DescribeInstancesInput{
-		MaxResults: aws.Int64(1000),
-	}
-	for {
-		resp, err := ec2Client.DescribeInstances(req)
+	paginator := ec2.NewDescribeInstancesPaginator(ec2Client, &ec2.DescribeInstancesInput{
+		MaxResults: aws.Int32(1000),
+	})
+	for paginator.HasMorePages() {
+		resp, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, reservation := range resp.Reservations {
 			for _, instance := range reservation.Instances {
-				ip4 := aws.StringValue(instance.PublicIpAddress)
-				ip6 := aws.StringValue(instance.Ipv6Address)
-				privateIp4 := aws.StringValue(instance.PrivateIpAddress)
+				ip4 := aws.ToString(instance.PublicIpAddress)
+				ip6 := aws.ToString(instance.Ipv6Address)
+				privateIp4 := aws.ToString(instance.PrivateIpAddress)
 
 				if privateIp4 != "" {
 					list.Append(&schema.Resource{
@@ -86,42 +63,28 @@ func (i *instanceProvider) getEC2Resources(ec2Client *ec2.EC2) (*schema.Resource
 				})
 			}
 		}
-		if aws.StringValue(resp.NextToken) == "" {
-			break
-		}
-		req.SetNextToken(aws.StringValue(resp.NextToken))
 	}
 	return list, nil
 }
 
-func (i *instanceProvider) getEc2Clients(region *string) []*ec2.EC2 {
-	endpoint := fmt.Sprintf("https://ec2.%s.amazonaws.com", aws.StringValue(region))
-	ec2Clients := make([]*ec2.EC2, 0)
-
-	ec2Client := ec2.New(
-		i.session,
-		aws.NewConfig().WithEndpoint(endpoint),
-		aws.NewConfig().WithRegion(aws.StringValue(region)),
-	)
-	ec2Clients = append(ec2Clients, ec2Client)
-
-	if i.options.AssumeRoleName == "" || len(i.options.AccountIds) < 1 {
-		return ec2Clients
-	}
-
-	for _, accountId := range i.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, i.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(i.session, roleARN)
-
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      region,
-			Credentials: creds,
-		})
-		if err != nil {
-			continue
+// describeInstances returns the ec2 instances by id, looked up in batches
+func describeInstances(ctx context.Context, ec2Client *ec2.Client, instanceIDs []string) (map[string]types.Instance, error) {
+	instances := make(map[string]types.Instance)
+	const chunkSize = 200
+	for start := 0; start < len(instanceIDs); start += chunkSize {
+		end := min(start+chunkSize, len(instanceIDs))
+		paginator := ec2.NewDescribeInstancesPaginator(ec2Client, &ec2.DescribeInstancesInput{InstanceIds: instanceIDs[start:end]})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return instances, err
+			}
+			for _, reservation := range page.Reservations {
+				for _, instance := range reservation.Instances {
+					instances[aws.ToString(instance.InstanceId)] = instance
+				}
+			}
 		}
-
-		ec2Clients = append(ec2Clients, ec2.New(assumeSession))
 	}
-	return ec2Clients
+	return instances, nil
 }
@@ -3,26 +3,27 @@ package aws
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"sort"
 	"strings"
 	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/apigateway"
-	"github.com/aws/aws-sdk-go/service/ec2"
-	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/apigateway"
+	apigatewaytypes "github.com/aws/aws-sdk-go-v2/service/apigateway/types"
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2"
+	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	"github.com/aws/aws-sdk-go-v2/service/lambda/types"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
 
-// apiGatewayProvider is a provider for AWS API Gateway resources
+// lambdaAndapiGatewayProvider is a provider for AWS API Gateway (REST, HTTP
+// and WebSocket APIs and custom domains) and Lambda function URL resources
 type lambdaAndapiGatewayProvider struct {
-	options      ProviderOptions
-	lambdaClient *lambda.Lambda
-	apiGateway   *apigateway.APIGateway
-	session      *session.Session
-	regions      *ec2.DescribeRegionsOutput
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
 }
 
 // GetResource returns all the resources in the store for a provider.
@@ -31,147 +32,358 @@ func (ap *lambdaAndapiGatewayProvider) GetResource(ctx context.Context) (*schema
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 
-	for _, region := range ap.regions.Regions {
-		apigatewayClients, lambdaClients := ap.getApiGatewayAndLamdaClients(region.RegionName)
-		for index := range len(apigatewayClients) {
-			wg.Add(1)
-
-			go func(regionName string, gatewayClient *apigateway.APIGateway, lambdaClient *lambda.Lambda) {
-				defer wg.Done()
-				if resources, err := ap.listAPIGatewayResources(regionName, gatewayClient, lambdaClient); err == nil {
-					mu.Lock()
-					list.Merge(resources)
-					mu.Unlock()
-				}
-			}(*region.RegionName, apigatewayClients[index], lambdaClients[index])
-		}
+	listers := make([]regionalLister, 0, 2)
+	if ap.options.Services.Has("apigateway") {
+		listers = append(listers, ap.listAPIGatewayResources)
+	}
+	if ap.options.Services.Has("lambda") {
+		listers = append(listers, ap.listFunctionURLs)
+	}
+
+	for _, lister := range listers {
+		wg.Add(1)
+
+		go func(lister regionalLister) {
+			defer wg.Done()
+
+			resources := listAllRegions(ctx, ap.configs, ap.regions, lister)
+			mu.Lock()
+			list.Merge(resources)
+			mu.Unlock()
+		}(lister)
 	}
 	wg.Wait()
 	return list, nil
 }
 
-func (ap *lambdaAndapiGatewayProvider) listAPIGatewayResources(regionName string, apiGateway *apigateway.APIGateway, lambdaClient *lambda.Lambda) (*schema.Resources, error) {
+func (ap *lambdaAndapiGatewayProvider) listAPIGatewayResources(ctx context.Context, cfg aws.Config, regionName string) (*schema.Resources, error) {
 	list := schema.NewResources()
-	apis, err := apiGateway.GetRestApis(&apigateway.GetRestApisInput{Limit: aws.Int64(500)})
+	apiGateway := newClient(cfg, regionName, apigateway.NewFromConfig)
+	apiGatewayV2 := newClient(cfg, regionName, apigatewayv2.NewFromConfig)
+
+	restAPIs, err := ap.listRestAPIs(ctx, regionName, apiGateway)
 	if err != nil {
-		return nil, errors.Wrap(err, "could not list APIs")
+		return nil, err
 	}
-	// List Lambda functions and create a mapping of function ARN to function name
-	lambdaFunctions, err := ap.getLambdaFunctions(lambdaClient)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not list Lambda functions")
-	}
-	lambdaFunctionMapping := make(map[string]string)
-	for _, lambdaFunction := range lambdaFunctions {
-		lambdaFunctionMapping[*lambdaFunction.FunctionArn] = *lambdaFunction.FunctionName
-	}
-	// Iterate over each API Gateway resource
-	for _, api := range apis.Items {
-		apiBaseURL := fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com", *api.Id, regionName)
-		list.Append(&schema.Resource{
-			Provider: "aws",
-			ID:       *api.Id,
-			DNSName:  apiBaseURL,
-			Public:   true,
-			Service:  "apigateway",
-		})
-		// Get resources for the API
-		resourceReq := &apigateway.GetResourcesInput{
-			RestApiId: api.Id,
-			Limit:     aws.Int64(100),
+	list.Merge(restAPIs)
+
+	if httpAPIs, err := ap.listHTTPAPIs(ctx, regionName, apiGatewayV2); err == nil {
+		list.Merge(httpAPIs)
+	}
+	if domains, err := ap.listCustomDomains(ctx, regionName, apiGateway, apiGatewayV2); err == nil {
+		list.Merge(domains)
+	}
+	return list, nil
+}
+
+// listRestAPIs lists the REST APIs along with their stages and lambda integrations
+func (ap *lambdaAndapiGatewayProvider) listRestAPIs(ctx context.Context, regionName string, apiGateway *apigateway.Client) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	paginator := apigateway.NewGetRestApisPaginator(apiGateway, &apigateway.GetRestApisInput{Limit: aws.Int32(500)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list APIs")
 		}
-		for {
-			resources, err := apiGateway.GetResources(resourceReq)
-			if err != nil {
-				return nil, errors.Wrapf(err, "could not get resources for API %s", *api.Id)
-			}
+		for _, api := range page.Items {
+			list.Append(ap.restAPIResource(ctx, apiGateway, regionName, api))
+		}
+	}
+	return list, nil
+}
 
-			for _, resource := range resources.Items {
-				// List methods for the resource
-				for _, method := range resource.ResourceMethods {
-					if method == nil || method.HttpMethod == nil {
-						continue
-					}
-					integration, err := apiGateway.GetIntegration(&apigateway.GetIntegrationInput{
-						RestApiId:  api.Id,
-						ResourceId: resource.Id,
-						HttpMethod: aws.String(*method.HttpMethod),
-					})
-					if err != nil {
-						continue
-					}
-					// Check if the integration type is AWS_PROXY (indicating Lambda integration)
-					if integration.Type != nil && *integration.Type == "AWS_PROXY" {
-						functionARN := extractLambdaARN(*integration.Uri)
-						if functionName, ok := lambdaFunctionMapping[functionARN]; ok {
-							apiURLWithLambda := fmt.Sprintf("%s/lambda/%s", apiBaseURL, functionName)
-							list.Append(&schema.Resource{
-								Provider: "aws",
-								ID:       *api.Id,
-								DNSName:  apiURLWithLambda,
-								Public:   true,
-								Service:  "lambda",
-							})
-						}
-					}
+// restAPIResource returns the execute-api endpoint of a REST API with its stages and lambda integrations
+func (ap *lambdaAndapiGatewayProvider) restAPIResource(ctx context.Context, apiGateway *apigateway.Client, regionName string, api apigatewaytypes.RestApi) *schema.Resource {
+	apiID := aws.ToString(api.Id)
+	host := fmt.Sprintf("%s.execute-api.%s.amazonaws.com", apiID, regionName)
+	metadata := map[string]string{
+		"region":   regionName,
+		"api_id":   apiID,
+		"api_name": aws.ToString(api.Name),
+		"api_type": "rest",
+		"url":      "https://" + host,
+	}
+	if api.EndpointConfiguration != nil {
+		metadata["endpoint_type"] = joinEnums(api.EndpointConfiguration.Types)
+	}
+	if api.DisableExecuteApiEndpoint {
+		metadata["execute_api_endpoint_disabled"] = "true"
+	}
+	if stages, err := apiGateway.GetStages(ctx, &apigateway.GetStagesInput{RestApiId: api.Id}); err == nil {
+		var names []string
+		for _, stage := range stages.Item {
+			names = append(names, aws.ToString(stage.StageName))
+		}
+		metadata["stages"] = strings.Join(names, ",")
+	}
+	if functions := ap.restAPILambdaFunctions(ctx, apiGateway, api.Id); len(functions) > 0 {
+		metadata["lambda_functions"] = strings.Join(functions, ",")
+	}
+
+	return &schema.Resource{
+		ID:       ap.options.Id,
+		Provider: providerName,
+		DNSName:  host,
+		Public:   true,
+		Service:  "apigateway",
+		Metadata: cleanMetadata(metadata),
+	}
+}
+
+// restAPILambdaFunctions returns the names of the lambda functions integrated with an API
+func (ap *lambdaAndapiGatewayProvider) restAPILambdaFunctions(ctx context.Context, apiGateway *apigateway.Client, apiID *string) []string {
+	functions := make(map[string]struct{})
+	paginator := apigateway.NewGetResourcesPaginator(apiGateway, &apigateway.GetResourcesInput{
+		RestApiId: apiID,
+		Limit:     aws.Int32(500),
+		Embed:     []string{"methods"},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			break
+		}
+		for _, resource := range page.Items {
+			for _, method := range resource.ResourceMethods {
+				if method.MethodIntegration == nil {
+					continue
+				}
+				// AWS_PROXY and AWS integrations with a lambda uri invoke a function
+				if name := lambdaFunctionName(extractLambdaARN(aws.ToString(method.MethodIntegration.Uri))); name != "" {
+					functions[name] = struct{}{}
 				}
 			}
+		}
+	}
 
-			if aws.StringValue(resources.Position) == "" {
-				break
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// listHTTPAPIs lists the HTTP and WebSocket APIs of API Gateway v2
+func (ap *lambdaAndapiGatewayProvider) listHTTPAPIs(ctx context.Context, regionName string, apiGatewayV2 *apigatewayv2.Client) (*schema.Resources, error) {
+	list := schema.NewResources()
+	req := &apigatewayv2.GetApisInput{MaxResults: aws.String("500")}
+	for {
+		apis, err := apiGatewayV2.GetApis(ctx, req)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list v2 APIs")
+		}
+		for _, api := range apis.Items {
+			endpoint := aws.ToString(api.ApiEndpoint)
+			parsed, err := url.Parse(endpoint)
+			if err != nil || parsed.Hostname() == "" {
+				continue
+			}
+			metadata := map[string]string{
+				"region":   regionName,
+				"api_id":   aws.ToString(api.ApiId),
+				"api_name": aws.ToString(api.Name),
+				"api_type": strings.ToLower(string(api.ProtocolType)),
+				"url":      endpoint,
 			}
-			resourceReq.SetPosition(*resources.Position)
+			if aws.ToBool(api.DisableExecuteApiEndpoint) {
+				metadata["execute_api_endpoint_disabled"] = "true"
+			}
+			list.Append(&schema.Resource{
+				ID:       ap.options.Id,
+				Provider: providerName,
+				DNSName:  parsed.Hostname(),
+				Public:   true,
+				Service:  "apigateway",
+				Metadata: cleanMetadata(metadata),
+			})
+		}
+		if aws.ToString(apis.NextToken) == "" {
+			break
 		}
+		req.NextToken = apis.NextToken
 	}
 	return list, nil
 }
 
-func (ap *lambdaAndapiGatewayProvider) getLambdaFunctions(lambdaClient *lambda.Lambda) ([]*lambda.FunctionConfiguration, error) {
-	var lambdaFunctions []*lambda.FunctionConfiguration
-	lambdaReq := &lambda.ListFunctionsInput{MaxItems: aws.Int64(20)}
+// listCustomDomains lists the custom domain names with their api mappings,
+// along with the api gateway domain names they point to.
+func (ap *lambdaAndapiGatewayProvider) listCustomDomains(ctx context.Context, regionName string, apiGateway *apigateway.Client, apiGatewayV2 *apigatewayv2.Client) (*schema.Resources, error) {
+	list := schema.NewResources()
+	seen := make(map[string]struct{})
+
+	appendDomain := func(domain, target, endpointType, certificateArn string, mappings []string) {
+		metadata := cleanMetadata(map[string]string{
+			"region":          regionName,
+			"custom_domain":   domain,
+			"target":          target,
+			"endpoint_type":   endpointType,
+			"certificate_arn": certificateArn,
+			"api_mappings":    strings.Join(mappings, ","),
+			"url":             "https://" + domain,
+		})
+		for _, host := range []string{domain, target} {
+			if host == "" {
+				continue
+			}
+			list.Append(&schema.Resource{
+				ID:       ap.options.Id,
+				Provider: providerName,
+				DNSName:  host,
+				Public:   true,
+				Service:  "apigateway",
+				Metadata: metadata,
+			})
+		}
+	}
+
+	// edge optimized domains are only returned by the v1 api
+	paginator := apigateway.NewGetDomainNamesPaginator(apiGateway, &apigateway.GetDomainNamesInput{Limit: aws.Int32(500)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list custom domains")
+		}
+		for _, domain := range page.Items {
+			name := aws.ToString(domain.DomainName)
+			seen[name] = struct{}{}
+
+			var mappings []string
+			mappingPaginator := apigateway.NewGetBasePathMappingsPaginator(apiGateway, &apigateway.GetBasePathMappingsInput{DomainName: domain.DomainName, Limit: aws.Int32(500)})
+			for mappingPaginator.HasMorePages() {
+				mappingPage, err := mappingPaginator.NextPage(ctx)
+				if err != nil {
+					break
+				}
+				for _, mapping := range mappingPage.Items {
+					mappings = append(mappings, apiMapping(aws.ToString(mapping.BasePath), aws.ToString(mapping.RestApiId), aws.ToString(mapping.Stage)))
+				}
+			}
+
+			target := aws.ToString(domain.RegionalDomainName)
+			if target == "" {
+				target = aws.ToString(domain.DistributionDomainName)
+			}
+			certificateArn := aws.ToString(domain.RegionalCertificateArn)
+			if certificateArn == "" {
+				certificateArn = aws.ToString(domain.CertificateArn)
+			}
+			var endpointType string
+			if domain.EndpointConfiguration != nil {
+				endpointType = joinEnums(domain.EndpointConfiguration.Types)
+			}
+			appendDomain(name, target, endpointType, certificateArn, mappings)
+		}
+	}
+
+	// the v2 api also returns the domains mapped to HTTP and WebSocket APIs
+	req := &apigatewayv2.GetDomainNamesInput{MaxResults: aws.String("500")}
 	for {
-		lambdaFuncs, err := lambdaClient.ListFunctions(lambdaReq)
+		domains, err := apiGatewayV2.GetDomainNames(ctx, req)
 		if err != nil {
-			return nil, errors.Wrap(err, "could not list Lambda functions")
+			break
 		}
-		lambdaFunctions = append(lambdaFunctions, lambdaFuncs.Functions...)
-		if aws.StringValue(lambdaFuncs.NextMarker) == "" {
+		for _, domain := range domains.Items {
+			name := aws.ToString(domain.DomainName)
+			if _, ok := seen[name]; ok {
+				continue
+			}
+
+			var mappings []string
+			mappingReq := &apigatewayv2.GetApiMappingsInput{DomainName: domain.DomainName, MaxResults: aws.String("500")}
+			for {
+				output, err := apiGatewayV2.GetApiMappings(ctx, mappingReq)
+				if err != nil {
+					break
+				}
+				for _, mapping := range output.Items {
+					mappings = append(mappings, apiMapping(aws.ToString(mapping.ApiMappingKey), aws.ToString(mapping.ApiId), aws.ToString(mapping.Stage)))
+				}
+				if aws.ToString(output.NextToken) == "" {
+					break
+				}
+				mappingReq.NextToken = output.NextToken
+			}
+			var target, endpointType, certificateArn string
+			if len(domain.DomainNameConfigurations) > 0 {
+				configuration := domain.DomainNameConfigurations[0]
+				target = aws.ToString(configuration.ApiGatewayDomainName)
+				endpointType = string(configuration.EndpointType)
+				certificateArn = aws.ToString(configuration.CertificateArn)
+			}
+			appendDomain(name, target, endpointType, certificateArn, mappings)
+		}
+		if aws.ToString(domains.NextToken) == "" {
 			break
 		}
-		lambdaReq.SetMarker(*lambdaFuncs.NextMarker)
+		req.NextToken = domains.NextToken
 	}
-	return lambdaFunctions, nil
+	return list, nil
 }
 
-func (ap *lambdaAndapiGatewayProvider) getApiGatewayAndLamdaClients(region *string) ([]*apigateway.APIGateway, []*lambda.Lambda) {
-	apiGatewayClients := make([]*apigateway.APIGateway, 0)
-	lambdaClients := make([]*lambda.Lambda, 0)
-
-	albClient := apigateway.New(ap.session, aws.NewConfig().WithRegion(*region))
-	apiGatewayClients = append(apiGatewayClients, albClient)
+// apiMapping formats a base path mapping as path=api/stage
+func apiMapping(path, apiID, stage string) string {
+	if path == "" || path == "(none)" {
+		path = "/"
+	}
+	return fmt.Sprintf("%s=%s/%s", path, apiID, stage)
+}
 
-	lambdaClient := lambda.New(ap.session, aws.NewConfig().WithRegion(*region))
-	lambdaClients = append(lambdaClients, lambdaClient)
+// listFunctionURLs lists the function urls of every lambda function, including aliases
+func (ap *lambdaAndapiGatewayProvider) listFunctionURLs(ctx context.Context, cfg aws.Config, regionName string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	lambdaClient := newClient(cfg, regionName, lambda.NewFromConfig)
 
-	if ap.options.AssumeRoleName == "" || len(ap.options.AccountIds) < 1 {
-		return apiGatewayClients, lambdaClients
+	functions, err := ap.getLambdaFunctions(ctx, lambdaClient)
+	if err != nil {
+		return nil, err
 	}
+	for _, function := range functions {
+		paginator := lambda.NewListFunctionUrlConfigsPaginator(lambdaClient, &lambda.ListFunctionUrlConfigsInput{FunctionName: function.FunctionArn, MaxItems: aws.Int32(50)})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				break
+			}
+			for _, config := range page.FunctionUrlConfigs {
+				functionURL := aws.ToString(config.FunctionUrl)
+				parsed, err := url.Parse(functionURL)
+				if err != nil || parsed.Hostname() == "" {
+					continue
+				}
+				list.Append(&schema.Resource{
+					ID:       ap.options.Id,
+					Provider: providerName,
+					DNSName:  parsed.Hostname(),
+					Public:   true,
+					Service:  "lambda",
+					Metadata: cleanMetadata(map[string]string{
+						"region":        regionName,
+						"function_name": aws.ToString(function.FunctionName),
+						"function_arn":  aws.ToString(config.FunctionArn),
+						"auth_type":     string(config.AuthType),
+						"invoke_mode":   string(config.InvokeMode),
+						"url":           functionURL,
+					}),
+				})
+			}
+		}
+	}
+	return list, nil
+}
 
-	for _, accountId := range ap.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, ap.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(ap.session, roleARN)
-
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      region,
-			Credentials: creds,
-		})
+func (ap *lambdaAndapiGatewayProvider) getLambdaFunctions(ctx context.Context, lambdaClient *lambda.Client) ([]types.FunctionConfiguration, error) {
+	var lambdaFunctions []types.FunctionConfiguration
+	paginator := lambda.NewListFunctionsPaginator(lambdaClient, &lambda.ListFunctionsInput{MaxItems: aws.Int32(50)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
 		if err != nil {
-			continue
+			return nil, errors.Wrap(err, "could not list Lambda functions")
 		}
-		apiGatewayClients = append(apiGatewayClients, apigateway.New(assumeSession))
-		lambdaClients = append(lambdaClients, lambda.New(assumeSession))
+		lambdaFunctions = append(lambdaFunctions, page.Functions...)
 	}
-	return apiGatewayClients, lambdaClients
+	return lambdaFunctions, nil
 }
 
 // extract Lambda function ARN from integration URI
@@ -183,3 +395,13 @@ func extractLambdaARN(uri string) string {
 	}
 	return ""
 }
+
+// lambdaFunctionName returns the function name of a lambda function arn
+// Example ARN: "arn:aws:lambda:us-west-2:123456789012:function:my-function:alias"
+func lambdaFunctionName(arn string) string {
+	parts := strings.Split(arn, ":")
+	if len(parts) < 7 || parts[2] != "lambda" || parts[5] != "function" {
+		return ""
+	}
+	return parts[6]
+}
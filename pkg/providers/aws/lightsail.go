@@ -2,22 +2,21 @@ package aws
 
 import (
 	"context"
-	"fmt"
-	"sync"
+	"net/url"
+	"strconv"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/lightsail"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/lightsail"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
 
-// lightsailProvider is an instance provider for AWS Lightsail API
+// lightsailProvider is a provider for AWS Lightsail instances, load balancers,
+// container services and distributions
 type lightsailProvider struct {
-	options  ProviderOptions
-	lsClient *lightsail.Lightsail
-	session  *session.Session
-	regions  []*lightsail.Region
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
 }
 
 func (l *lightsailProvider) name() string {
@@ -26,41 +25,27 @@ func (l *lightsailProvider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (l *lightsailProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, region := range l.regions {
-		for _, lsClient := range l.getLightsailClients(region.Name) {
-			wg.Add(1)
-
-			go func(client *lightsail.Lightsail) {
-				defer wg.Done()
-
-				if resources, err := l.listListsailResources(client); err == nil {
-					mu.Lock()
-					list.Merge(resources)
-					mu.Unlock()
-				}
-			}(lsClient)
-		}
-	}
-	wg.Wait()
+	list := listAllRegions(ctx, l.configs, l.regions, l.listListsailResources)
+
+	// distributions are global and only served by the us-east-1 endpoint
+	list.Merge(listAllAccounts(ctx, l.configs, l.listDistributions))
 	return list, nil
 }
 
-func (l *lightsailProvider) listListsailResources(lsClient *lightsail.Lightsail) (*schema.Resources, error) {
+func (l *lightsailProvider) listListsailResources(ctx context.Context, cfg aws.Config, regionName string) (*schema.Resources, error) {
 	list := schema.NewResources()
+	lsClient := newClient(cfg, regionName, lightsail.NewFromConfig)
+
 	req := &lightsail.GetInstancesInput{}
 	for {
-		resp, err := lsClient.GetInstances(req)
+		resp, err := lsClient.GetInstances(ctx, req)
 		if err != nil {
 			return nil, err
 		}
 
 		for _, instance := range resp.Instances {
-			privateIPv4 := aws.StringValue(instance.PrivateIpAddress)
-			publicIPv4 := aws.StringValue(instance.PublicIpAddress)
+			privateIPv4 := aws.ToString(instance.PrivateIpAddress)
+			publicIPv4 := aws.ToString(instance.PublicIpAddress)
 			resource := &schema.Resource{
 				ID:          l.options.Id,
 				Provider:    providerName,
@@ -71,47 +56,107 @@ func (l *lightsailProvider) listListsailResources(lsClient *lightsail.Lightsail)
 			}
 
 			if len(instance.Ipv6Addresses) > 0 {
-				resource.PublicIPv6 = aws.StringValue(instance.Ipv6Addresses[0])
+				resource.PublicIPv6 = instance.Ipv6Addresses[0]
 			}
 
 			list.Append(resource)
 		}
-		if aws.StringValue(resp.NextPageToken) == "" {
+		if aws.ToString(resp.NextPageToken) == "" {
 			break
 		}
 		req.PageToken = resp.NextPageToken
 	}
-	return list, nil
-}
-
-func (l *lightsailProvider) getLightsailClients(region *string) []*lightsail.Lightsail {
-	endpoint := fmt.Sprintf("https://lightsail.%s.amazonaws.com", aws.StringValue(region))
-	lightsailClients := make([]*lightsail.Lightsail, 0)
 
-	lightsailClient := lightsail.New(
-		l.session,
-		aws.NewConfig().WithEndpoint(endpoint),
-		aws.NewConfig().WithRegion(aws.StringValue(region)),
-	)
-	lightsailClients = append(lightsailClients, lightsailClient)
+	lbReq := &lightsail.GetLoadBalancersInput{}
+	for {
+		resp, err := lsClient.GetLoadBalancers(ctx, lbReq)
+		if err != nil {
+			break
+		}
+		for _, lb := range resp.LoadBalancers {
+			ports := make([]string, 0, len(lb.PublicPorts))
+			for _, port := range lb.PublicPorts {
+				ports = append(ports, strconv.FormatInt(int64(port), 10))
+			}
+			list.Append(l.hostResource(aws.ToString(lb.DnsName), map[string]string{
+				"region":        regionName,
+				"resource_type": "load_balancer",
+				"name":          aws.ToString(lb.Name),
+				"public_ports":  strings.Join(ports, ","),
+				"state":         string(lb.State),
+			}))
+		}
+		if aws.ToString(resp.NextPageToken) == "" {
+			break
+		}
+		lbReq.PageToken = resp.NextPageToken
+	}
 
-	if l.options.AssumeRoleName == "" || len(l.options.AccountIds) < 1 {
-		return lightsailClients
+	containers, err := lsClient.GetContainerServices(ctx, &lightsail.GetContainerServicesInput{})
+	if err != nil {
+		return list, nil
 	}
+	for _, service := range containers.ContainerServices {
+		metadata := map[string]string{
+			"region":        regionName,
+			"resource_type": "container_service",
+			"name":          aws.ToString(service.ContainerServiceName),
+			"url":           aws.ToString(service.Url),
+			"state":         string(service.State),
+		}
+		if parsed, err := url.Parse(aws.ToString(service.Url)); err == nil {
+			list.Append(l.hostResource(parsed.Hostname(), metadata))
+		}
+		// public domain names are keyed by the certificate they are served with
+		for _, domains := range service.PublicDomainNames {
+			for _, domain := range domains {
+				list.Append(l.hostResource(domain, metadata))
+			}
+		}
+	}
+	return list, nil
+}
 
-	for _, accountId := range l.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, l.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(l.session, roleARN)
+func (l *lightsailProvider) listDistributions(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	lsClient := newClient(cfg, region, lightsail.NewFromConfig)
 
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      region,
-			Credentials: creds,
-		})
+	req := &lightsail.GetDistributionsInput{}
+	for {
+		resp, err := lsClient.GetDistributions(ctx, req)
 		if err != nil {
-			continue
+			return nil, err
+		}
+		for _, distribution := range resp.Distributions {
+			metadata := map[string]string{
+				"resource_type": "distribution",
+				"name":          aws.ToString(distribution.Name),
+				"origin":        aws.ToString(distribution.OriginPublicDNS),
+				"enabled":       strconv.FormatBool(aws.ToBool(distribution.IsEnabled)),
+			}
+			if distribution.Origin != nil {
+				metadata["origin_name"] = aws.ToString(distribution.Origin.Name)
+			}
+			list.Append(l.hostResource(aws.ToString(distribution.DomainName), metadata))
+			for _, domain := range distribution.AlternativeDomainNames {
+				list.Append(l.hostResource(domain, metadata))
+			}
+		}
+		if aws.ToString(resp.NextPageToken) == "" {
+			break
 		}
+		req.PageToken = resp.NextPageToken
+	}
+	return list, nil
+}
 
-		lightsailClients = append(lightsailClients, lightsail.New(assumeSession))
+func (l *lightsailProvider) hostResource(host string, metadata map[string]string) *schema.Resource {
+	return &schema.Resource{
+		ID:       l.options.Id,
+		Provider: providerName,
+		DNSName:  host,
+		Public:   true,
+		Service:  l.name(),
+		Metadata: cleanMetadata(metadata),
 	}
-	return lightsailClients
 }
@@ -0,0 +1,76 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/opensearch"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// opensearchProvider is a provider for AWS OpenSearch (and Elasticsearch) domain endpoints
+type opensearchProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (op *opensearchProvider) name() string {
+	return "opensearch"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (op *opensearchProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, op.configs, op.regions, op.listOpenSearchResources), nil
+}
+
+func (op *opensearchProvider) listOpenSearchResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	client := newClient(cfg, region, opensearch.NewFromConfig)
+
+	names, err := client.ListDomainNames(ctx, &opensearch.ListDomainNamesInput{})
+	if err != nil {
+		return nil, err
+	}
+
+	// domains are described by batches of 5
+	const batchSize = 5
+	for start := 0; start < len(names.DomainNames); start += batchSize {
+		end := min(start+batchSize, len(names.DomainNames))
+		var domainNames []string
+		for _, domain := range names.DomainNames[start:end] {
+			domainNames = append(domainNames, aws.ToString(domain.DomainName))
+		}
+		output, err := client.DescribeDomains(ctx, &opensearch.DescribeDomainsInput{DomainNames: domainNames})
+		if err != nil {
+			continue
+		}
+
+		for _, domain := range output.DomainStatusList {
+			// domains outside of a vpc have a public endpoint
+			endpoints := make(map[string]bool)
+			if endpoint := aws.ToString(domain.Endpoint); endpoint != "" {
+				endpoints[endpoint] = true
+			}
+			for _, endpoint := range domain.Endpoints {
+				endpoints[endpoint] = false
+			}
+			if domain.DomainEndpointOptions != nil && aws.ToBool(domain.DomainEndpointOptions.CustomEndpointEnabled) {
+				endpoints[aws.ToString(domain.DomainEndpointOptions.CustomEndpoint)] = domain.VPCOptions == nil
+			}
+
+			for host, public := range endpoints {
+				if host == "" {
+					continue
+				}
+				list.Append(endpointResource(op.options.Id, op.name(), host, 443, public, map[string]string{
+					"region":         region,
+					"domain":         aws.ToString(domain.DomainName),
+					"engine_version": aws.ToString(domain.EngineVersion),
+					"endpoint_type":  "domain",
+				}))
+			}
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,131 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/rds"
+	"github.com/aws/aws-sdk-go-v2/service/rds/types"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// rdsProvider is a provider for AWS RDS and Aurora instances and cluster endpoints
+type rdsProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (rp *rdsProvider) name() string {
+	return "rds"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (rp *rdsProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, rp.configs, rp.regions, rp.listRDSResources), nil
+}
+
+// documentDB clusters are also returned by the rds api, they are
+// reported by the docdb service instead.
+var rdsEngineFilter = []types.Filter{{
+	Name: aws.String("engine"),
+	Values: []string{
+		"aurora-mysql", "aurora-postgresql", "mysql", "mariadb", "postgres", "neptune",
+		"oracle-ee", "oracle-ee-cdb", "oracle-se2", "oracle-se2-cdb",
+		"sqlserver-ee", "sqlserver-se", "sqlserver-ex", "sqlserver-web",
+		"custom-oracle-ee", "custom-sqlserver-ee", "custom-sqlserver-se", "custom-sqlserver-web", "db2-ae", "db2-se",
+	},
+}}
+
+func (rp *rdsProvider) listRDSResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	rdsClient := newClient(cfg, region, rds.NewFromConfig)
+
+	instancePublic := make(map[string]bool)
+	instances := rds.NewDescribeDBInstancesPaginator(rdsClient, &rds.DescribeDBInstancesInput{Filters: rdsEngineFilter})
+	for instances.HasMorePages() {
+		page, err := instances.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range page.DBInstances {
+			instancePublic[aws.ToString(instance.DBInstanceIdentifier)] = aws.ToBool(instance.PubliclyAccessible)
+			if instance.Endpoint == nil || aws.ToString(instance.Endpoint.Address) == "" {
+				continue
+			}
+			list.Append(endpointResource(rp.options.Id, rp.name(), aws.ToString(instance.Endpoint.Address), int64(aws.ToInt32(instance.Endpoint.Port)), aws.ToBool(instance.PubliclyAccessible), map[string]string{
+				"region":        region,
+				"identifier":    aws.ToString(instance.DBInstanceIdentifier),
+				"cluster":       aws.ToString(instance.DBClusterIdentifier),
+				"engine":        aws.ToString(instance.Engine),
+				"endpoint_type": "instance",
+			}))
+		}
+	}
+
+	// cluster endpoints resolve to the instances of the cluster, they are
+	// reported as public if the cluster or any of its members is, aurora
+	// clusters leaving the flag of the cluster unset. Custom endpoints reuse
+	// the flag of their cluster.
+	clusterPublic := make(map[string]bool)
+	clusters := rds.NewDescribeDBClustersPaginator(rdsClient, &rds.DescribeDBClustersInput{Filters: rdsEngineFilter})
+	for clusters.HasMorePages() {
+		page, err := clusters.NextPage(ctx)
+		if err != nil {
+			// the instances are kept as listAllRegions drops the results of failed regions
+			gologger.Warning().Msgf("Could not list rds clusters in %s: %s", region, err)
+			return list, nil
+		}
+		for _, cluster := range page.DBClusters {
+			identifier := aws.ToString(cluster.DBClusterIdentifier)
+			public := aws.ToBool(cluster.PubliclyAccessible)
+			for _, member := range cluster.DBClusterMembers {
+				public = public || instancePublic[aws.ToString(member.DBInstanceIdentifier)]
+			}
+			clusterPublic[identifier] = public
+
+			endpoints := map[string]string{
+				"writer": aws.ToString(cluster.Endpoint),
+				"reader": aws.ToString(cluster.ReaderEndpoint),
+			}
+			for endpointType, host := range endpoints {
+				if host == "" {
+					continue
+				}
+				list.Append(endpointResource(rp.options.Id, rp.name(), host, int64(aws.ToInt32(cluster.Port)), public, map[string]string{
+					"region":        region,
+					"cluster":       identifier,
+					"engine":        aws.ToString(cluster.Engine),
+					"endpoint_type": endpointType,
+				}))
+			}
+		}
+	}
+
+	endpoints := rds.NewDescribeDBClusterEndpointsPaginator(rdsClient, &rds.DescribeDBClusterEndpointsInput{})
+	for endpoints.HasMorePages() {
+		page, err := endpoints.NextPage(ctx)
+		if err != nil {
+			gologger.Warning().Msgf("Could not list rds cluster endpoints in %s: %s", region, err)
+			return list, nil
+		}
+		for _, endpoint := range page.DBClusterEndpoints {
+			// writer and reader endpoints were already reported with the cluster
+			if aws.ToString(endpoint.EndpointType) != "CUSTOM" {
+				continue
+			}
+			identifier := aws.ToString(endpoint.DBClusterIdentifier)
+			if _, ok := clusterPublic[identifier]; !ok {
+				continue
+			}
+			list.Append(endpointResource(rp.options.Id, rp.name(), aws.ToString(endpoint.Endpoint), 0, clusterPublic[identifier], map[string]string{
+				"region":        region,
+				"cluster":       identifier,
+				"identifier":    aws.ToString(endpoint.DBClusterEndpointIdentifier),
+				"endpoint_type": "custom",
+			}))
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,49 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/redshift"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// redshiftProvider is a provider for AWS Redshift cluster endpoints
+type redshiftProvider struct {
+	options ProviderOptions
+	configs []aws.Config
+	regions []string
+}
+
+func (rp *redshiftProvider) name() string {
+	return "redshift"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (rp *redshiftProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	return listAllRegions(ctx, rp.configs, rp.regions, rp.listRedshiftResources), nil
+}
+
+func (rp *redshiftProvider) listRedshiftResources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	redshiftClient := newClient(cfg, region, redshift.NewFromConfig)
+
+	paginator := redshift.NewDescribeClustersPaginator(redshiftClient, &redshift.DescribeClustersInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range page.Clusters {
+			if cluster.Endpoint == nil || aws.ToString(cluster.Endpoint.Address) == "" {
+				continue
+			}
+			list.Append(endpointResource(rp.options.Id, rp.name(), aws.ToString(cluster.Endpoint.Address), int64(aws.ToInt32(cluster.Endpoint.Port)), aws.ToBool(cluster.PubliclyAccessible), map[string]string{
+				"region":        region,
+				"cluster":       aws.ToString(cluster.ClusterIdentifier),
+				"endpoint_type": "cluster",
+			}))
+		}
+	}
+	return list, nil
+}
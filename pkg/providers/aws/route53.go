@@ -3,13 +3,14 @@ package aws
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strconv"
 	"strings"
-	"sync"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/route53"
+	"github.com/aws/aws-sdk-go-v2/service/route53/types"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
@@ -17,8 +18,7 @@ import (
 // route53Provider is a provider for aws Route53 API
 type route53Provider struct {
 	options ProviderOptions
-	route53 *route53.Route53
-	session *session.Session
+	configs []aws.Config
 }
 
 func (r *route53Provider) name() string {
@@ -27,126 +27,221 @@ func (r *route53Provider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (r *route53Provider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-
-	for _, route53Client := range r.getRoute53Clients() {
-		wg.Add(1)
-
-		go func(client *route53.Route53) {
-			defer wg.Done()
+	return listAllAccounts(ctx, r.configs, r.listAccountZones), nil
+}
 
-			zones, err := r.getHostedZones(client)
-			if err != nil {
-				return
-			}
-			if resources, err := r.listResourcesByZone(zones, client); err == nil {
-				mu.Lock()
-				list.Merge(resources)
-				mu.Unlock()
-			}
-		}(route53Client)
+func (r *route53Provider) listAccountZones(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	client := newClient(cfg, region, route53.NewFromConfig)
+	zones, err := r.getHostedZones(ctx, client)
+	if err != nil {
+		return nil, err
 	}
-	wg.Wait()
-	return list, nil
+	var accountID string
+	if identity, err := newClient(cfg, region, sts.NewFromConfig).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}); err == nil {
+		accountID = aws.ToString(identity.Account)
+	}
+	return r.listResourcesByZone(ctx, zones, client, accountID)
 }
 
-func (r *route53Provider) getHostedZones(client *route53.Route53) ([]*route53.HostedZone, error) {
-	zones := make([]*route53.HostedZone, 0)
-	req := &route53.ListHostedZonesInput{}
-	for {
-		zoneOutput, err := client.ListHostedZones(req)
+func (r *route53Provider) getHostedZones(ctx context.Context, client *route53.Client) ([]types.HostedZone, error) {
+	zones := make([]types.HostedZone, 0)
+	paginator := route53.NewListHostedZonesPaginator(client, &route53.ListHostedZonesInput{})
+	for paginator.HasMorePages() {
+		zoneOutput, err := paginator.NextPage(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "could not list hosted zones")
 		}
 		zones = append(zones, zoneOutput.HostedZones...)
-		if aws.BoolValue(zoneOutput.IsTruncated) && *zoneOutput.NextMarker != "" {
-			req.SetMarker(*zoneOutput.NextMarker)
-		} else {
-			break
-		}
 	}
 	return zones, nil
 }
 
-// listResourceRecords lists the resource records for a hosted route53 zone.
-func (r *route53Provider) listResourcesByZone(zones []*route53.HostedZone, client *route53.Route53) (*schema.Resources, error) {
+// listResourcesByZone lists every value of the address, cname and alias
+// records of the hosted zones, with the routing policy of each record set.
+func (r *route53Provider) listResourcesByZone(ctx context.Context, zones []types.HostedZone, client *route53.Client, accountID string) (*schema.Resources, error) {
 	list := schema.NewResources()
 	for _, zone := range zones {
-		public := !*zone.Config.PrivateZone
+		private := zone.Config != nil && zone.Config.PrivateZone
+		zoneMetadata := map[string]string{
+			"zone":         strings.TrimSuffix(aws.ToString(zone.Name), "."),
+			"zone_id":      strings.TrimPrefix(aws.ToString(zone.Id), "/hostedzone/"),
+			"private_zone": strconv.FormatBool(private),
+			"account_id":   accountID,
+		}
+		// private zones only resolve from their associated vpcs
+		if private {
+			zoneMetadata["vpcs"] = strings.Join(r.zoneVPCs(ctx, client, zone.Id), ",")
+		}
 
-		req := &route53.ListResourceRecordSetsInput{HostedZoneId: aws.String(*zone.Id)}
-		for {
-			sets, err := client.ListResourceRecordSets(req)
+		paginator := route53.NewListResourceRecordSetsPaginator(client, &route53.ListResourceRecordSetsInput{HostedZoneId: zone.Id})
+		for paginator.HasMorePages() {
+			sets, err := paginator.NextPage(ctx)
 			if err != nil {
 				return nil, errors.Wrap(err, "could not list resource_record set")
 			}
 			for _, item := range sets.ResourceRecordSets {
-				if *item.Type != "A" && *item.Type != "CNAME" && *item.Type != "AAAA" {
+				if item.Type != types.RRTypeA && item.Type != types.RRTypeCname && item.Type != types.RRTypeAaaa {
 					continue
 				}
-				name := strings.TrimSuffix(*item.Name, ".")
-
-				var record string
-				if len(item.ResourceRecords) >= 1 {
-					record = aws.StringValue(item.ResourceRecords[0].Value)
-				}
-				list.Append(&schema.Resource{
-					ID:       r.options.Id,
-					Public:   public,
-					DNSName:  name,
-					Provider: providerName,
-					Service:  r.name(),
-				})
-
-				resource := &schema.Resource{
-					ID:       r.options.Id,
-					Public:   public,
-					Provider: providerName,
-					Service:  r.name(),
-				}
-
-				//nolint
-				if *item.Type == "A" {
-					resource.PublicIPv4 = record
-				} else if *item.Type == "AAAA" {
-					resource.PublicIPv6 = record
-				}
-
-				list.Append(resource)
-			}
-			if aws.BoolValue(sets.IsTruncated) && *sets.NextRecordName != "" {
-				req.SetStartRecordName(*sets.NextRecordName)
-			} else {
-				break
+				list.Merge(r.recordSetResources(&item, zoneMetadata, !private))
 			}
 		}
 	}
 	return list, nil
 }
 
-func (r *route53Provider) getRoute53Clients() []*route53.Route53 {
-	route53Clients := make([]*route53.Route53, 0)
-	route53Clients = append(route53Clients, r.route53)
+// recordSetResources returns the name of a record set along with all its
+// values, or the target of an alias record.
+func (r *route53Provider) recordSetResources(item *types.ResourceRecordSet, zoneMetadata map[string]string, public bool) *schema.Resources {
+	list := schema.NewResources()
+	name := strings.TrimSuffix(aws.ToString(item.Name), ".")
 
-	if r.options.AssumeRoleName == "" || len(r.options.AccountIds) < 1 {
-		return route53Clients
+	values := make([]string, 0, len(item.ResourceRecords))
+	for _, record := range item.ResourceRecords {
+		values = append(values, aws.ToString(record.Value))
+	}
+	metadata := maps.Clone(zoneMetadata)
+	metadata["record_type"] = string(item.Type)
+	metadata["routing_policy"] = routingPolicy(item)
+	metadata["set_identifier"] = aws.ToString(item.SetIdentifier)
+	metadata["health_check_id"] = aws.ToString(item.HealthCheckId)
+	metadata["values"] = strings.Join(values, ",")
+	if item.TTL != nil {
+		metadata["ttl"] = strconv.FormatInt(aws.ToInt64(item.TTL), 10)
+	}
+	if item.Weight != nil {
+		metadata["weight"] = strconv.FormatInt(aws.ToInt64(item.Weight), 10)
+	}
+	if item.Region != "" {
+		metadata["latency_region"] = string(item.Region)
+	}
+	if location := item.GeoLocation; location != nil {
+		metadata["location"] = strings.Trim(strings.Join([]string{aws.ToString(location.ContinentCode), aws.ToString(location.CountryCode), aws.ToString(location.SubdivisionCode)}, "/"), "/")
+	}
+	if item.Failover != "" {
+		metadata["failover"] = strings.ToLower(string(item.Failover))
 	}
 
-	for _, accountId := range r.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, r.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(r.session, roleARN)
-
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      aws.String("us-east-1"),
-			Credentials: creds,
-		})
-		if err != nil {
+	var aliasTarget string
+	if item.AliasTarget != nil {
+		aliasTarget = strings.TrimSuffix(aws.ToString(item.AliasTarget.DNSName), ".")
+		aliasTarget = strings.TrimPrefix(aliasTarget, "dualstack.")
+		metadata["alias_target"] = aliasTarget
+		metadata["alias_target_type"] = aliasTargetType(aliasTarget, aws.ToString(item.AliasTarget.HostedZoneId) == zoneMetadata["zone_id"])
+	}
+	metadata = cleanMetadata(metadata)
+
+	list.Append(&schema.Resource{
+		ID:       r.options.Id,
+		Public:   public,
+		DNSName:  name,
+		Provider: providerName,
+		Service:  r.name(),
+		Metadata: metadata,
+	})
+	for _, value := range values {
+		resource := &schema.Resource{
+			ID:       r.options.Id,
+			Public:   public,
+			Provider: providerName,
+			Service:  r.name(),
+			Metadata: metadata,
+		}
+		switch item.Type {
+		case types.RRTypeA:
+			resource.PublicIPv4 = value
+		case types.RRTypeAaaa:
+			resource.PublicIPv6 = value
+		default:
+			// cname targets are only kept in the metadata of the name
 			continue
 		}
+		list.Append(resource)
+	}
+	// alias targets are aws resources of the account itself, except the
+	// records of the zone and the endpoints shared by every account
+	if aliasTarget != "" && metadata["alias_target_type"] != "record" && !sharedAliasTarget(aliasTarget, metadata["alias_target_type"]) {
+		list.Append(&schema.Resource{
+			ID:       r.options.Id,
+			Public:   public,
+			DNSName:  aliasTarget,
+			Provider: providerName,
+			Service:  r.name(),
+			Metadata: metadata,
+		})
+	}
+	return list
+}
+
+// zoneVPCs returns the <region>/<vpc id> of the vpcs associated with a private zone
+func (r *route53Provider) zoneVPCs(ctx context.Context, client *route53.Client, zoneID *string) []string {
+	output, err := client.GetHostedZone(ctx, &route53.GetHostedZoneInput{Id: zoneID})
+	if err != nil {
+		return nil
+	}
+	vpcs := make([]string, 0, len(output.VPCs))
+	for _, vpc := range output.VPCs {
+		vpcs = append(vpcs, fmt.Sprintf("%s/%s", vpc.VPCRegion, aws.ToString(vpc.VPCId)))
+	}
+	return vpcs
+}
+
+// routingPolicy returns the routing policy of a record set
+func routingPolicy(item *types.ResourceRecordSet) string {
+	switch {
+	case item.Weight != nil:
+		return "weighted"
+	case item.Region != "":
+		return "latency"
+	case item.GeoLocation != nil:
+		return "geolocation"
+	case item.GeoProximityLocation != nil:
+		return "geoproximity"
+	case item.Failover != "":
+		return "failover"
+	case item.CidrRoutingConfig != nil:
+		return "ip_based"
+	case aws.ToBool(item.MultiValueAnswer):
+		return "multivalue"
+	default:
+		return "simple"
+	}
+}
+
+// sharedAliasTarget returns true for the regional endpoints shared by every
+// account, such as the s3 website endpoints, which have no label of their
+// own before the service and region labels
+func sharedAliasTarget(target, targetType string) bool {
+	if targetType == "s3_website" {
+		return true
+	}
+	return strings.HasSuffix(target, ".amazonaws.com") && strings.Count(target, ".") <= 3
+}
 
-		route53Clients = append(route53Clients, route53.New(assumeSession))
+// aliasTargetType returns the kind of resource an alias record points to
+func aliasTargetType(target string, sameZone bool) string {
+	switch {
+	case sameZone:
+		return "record"
+	case strings.HasSuffix(target, ".elb.amazonaws.com"):
+		return "load_balancer"
+	case strings.HasSuffix(target, ".cloudfront.net"):
+		return "cloudfront"
+	case strings.HasPrefix(target, "s3-website") || strings.Contains(target, ".s3-website"):
+		return "s3_website"
+	case strings.Contains(target, ".execute-api."):
+		return "apigateway"
+	case strings.HasSuffix(target, ".elasticbeanstalk.com"):
+		return "elasticbeanstalk"
+	case strings.HasSuffix(target, ".awsglobalaccelerator.com"):
+		return "global_accelerator"
+	case strings.Contains(target, ".vpce.") || strings.Contains(target, ".vpce-svc-"):
+		return "vpc_endpoint"
+	case strings.HasSuffix(target, ".amplifyapp.com"):
+		return "amplify"
+	case strings.HasSuffix(target, ".appsync-api.amazonaws.com") || strings.Contains(target, ".appsync-api."):
+		return "appsync"
+	default:
+		return "other"
 	}
-	return route53Clients
 }
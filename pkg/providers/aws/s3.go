@@ -3,12 +3,16 @@ package aws
 import (
 	"context"
 	"fmt"
-	"sync"
+	"maps"
+	"strconv"
+	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
@@ -16,8 +20,7 @@ import (
 // s3Provider is a provider for aws S3 API
 type s3Provider struct {
 	options ProviderOptions
-	s3      *s3.S3
-	session *session.Session
+	configs []aws.Config
 }
 
 func (s *s3Provider) name() string {
@@ -26,68 +29,230 @@ func (s *s3Provider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (s *s3Provider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	var wg sync.WaitGroup
-	var mu sync.Mutex
+	return listAllAccounts(ctx, s.configs, s.getS3Resources), nil
+}
+
+// publicAccessBlock is the block public access configuration that applies to a bucket
+type publicAccessBlock struct {
+	blockPublicAcls       bool
+	ignorePublicAcls      bool
+	blockPublicPolicy     bool
+	restrictPublicBuckets bool
+}
 
-	for _, s3Client := range s.getS3Clients() {
-		wg.Add(1)
+// merge combines the account and bucket settings, the most restrictive wins
+func (p publicAccessBlock) merge(config *types.PublicAccessBlockConfiguration) publicAccessBlock {
+	if config == nil {
+		return p
+	}
+	p.blockPublicAcls = p.blockPublicAcls || aws.ToBool(config.BlockPublicAcls)
+	p.ignorePublicAcls = p.ignorePublicAcls || aws.ToBool(config.IgnorePublicAcls)
+	p.blockPublicPolicy = p.blockPublicPolicy || aws.ToBool(config.BlockPublicPolicy)
+	p.restrictPublicBuckets = p.restrictPublicBuckets || aws.ToBool(config.RestrictPublicBuckets)
+	return p
+}
 
-		go func(s3Client *s3.S3) {
-			defer wg.Done()
+// String returns full, partial or none depending on the enabled settings
+func (p publicAccessBlock) String() string {
+	enabled := 0
+	for _, setting := range []bool{p.blockPublicAcls, p.ignorePublicAcls, p.blockPublicPolicy, p.restrictPublicBuckets} {
+		if setting {
+			enabled++
+		}
+	}
+	switch enabled {
+	case 4:
+		return "full"
+	case 0:
+		return "none"
+	default:
+		return "partial"
+	}
+}
+
+func (s *s3Provider) getS3Resources(ctx context.Context, cfg aws.Config, region string) (*schema.Resources, error) {
+	list := schema.NewResources()
+	s3Client := newClient(cfg, region, s3.NewFromConfig)
+	accountBlock := accountPublicAccessBlock(ctx, cfg, region)
+
+	// bucket level calls must be sent to the region of the bucket
+	regionalClients := map[string]*s3.Client{region: s3Client}
+	paginator := s3.NewListBucketsPaginator(s3Client, &s3.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		listBucketsOutput, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not list s3 buckets")
+		}
 
-			if resources, err := s.getS3Resources(s3Client); err == nil {
-				mu.Lock()
-				list.Merge(resources)
-				mu.Unlock()
+		for _, bucket := range listBucketsOutput.Buckets {
+			bucketName := aws.ToString(bucket.Name)
+			bucketRegion := aws.ToString(bucket.BucketRegion)
+			if bucketRegion == "" {
+				bucketRegion = s.bucketRegion(ctx, s3Client, bucketName, region)
 			}
-		}(s3Client)
+			client, ok := regionalClients[bucketRegion]
+			if !ok {
+				client = newClient(cfg, bucketRegion, s3.NewFromConfig)
+				regionalClients[bucketRegion] = client
+			}
+			list.Merge(s.bucketResources(ctx, client, bucketName, bucketRegion, accountBlock))
+		}
 	}
-	wg.Wait()
 	return list, nil
 }
 
-func (s *s3Provider) getS3Resources(s3Client *s3.S3) (*schema.Resources, error) {
+// bucketResources returns the regional virtual-hosted and website
+// endpoints of a bucket along with its public access status
+func (s *s3Provider) bucketResources(ctx context.Context, client *s3.Client, bucket, region string, accountBlock publicAccessBlock) *schema.Resources {
 	list := schema.NewResources()
-	req := &s3.ListBucketsInput{}
-	listBucketsOutput, err := s3Client.ListBuckets(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "could not list s3 buckets")
+	metadata := map[string]string{
+		"region": region,
+		"bucket": bucket,
+	}
+
+	block := accountBlock
+	if output, err := client.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: aws.String(bucket)}); err == nil {
+		block = block.merge(output.PublicAccessBlockConfiguration)
+	}
+	metadata["public_access_block"] = block.String()
+
+	// a status that could not be read is left out rather than reported as private
+	var public, known bool
+	if policyPublic, ok := bucketPolicyPublic(ctx, client, bucket); ok {
+		metadata["policy_public"] = strconv.FormatBool(policyPublic)
+		public = public || (policyPublic && !block.restrictPublicBuckets)
+		known = true
 	}
+	if aclPublic, ok := bucketACLPublic(ctx, client, bucket); ok {
+		metadata["acl_public"] = strconv.FormatBool(aclPublic)
+		public = public || (aclPublic && !block.ignorePublicAcls)
+		known = true
+	}
+	if known {
+		metadata["public"] = strconv.FormatBool(public)
+	}
+
+	website := false
+	if _, err := client.GetBucketWebsite(ctx, &s3.GetBucketWebsiteInput{Bucket: aws.String(bucket)}); err == nil {
+		website = true
+	}
+	metadata["website"] = strconv.FormatBool(website)
 
-	for _, bucket := range listBucketsOutput.Buckets {
+	appendEndpoint := func(host, endpointType string) {
+		endpointMetadata := maps.Clone(metadata)
+		endpointMetadata["endpoint_type"] = endpointType
 		list.Append(&schema.Resource{
 			ID:       s.options.Id,
 			Public:   true,
-			DNSName:  fmt.Sprintf("%s.s3.amazonaws.com", aws.StringValue(bucket.Name)),
+			DNSName:  host,
 			Provider: providerName,
 			Service:  s.name(),
+			Metadata: endpointMetadata,
 		})
 	}
-	return list, nil
+	appendEndpoint(fmt.Sprintf("%s.s3.%s.%s", bucket, region, s3DomainSuffix(region)), "virtual_hosted")
+	if website {
+		appendEndpoint(s3WebsiteEndpoint(bucket, region), "website")
+	}
+	return list
 }
 
-func (s *s3Provider) getS3Clients() []*s3.S3 {
-	s3Cleints := make([]*s3.S3, 0)
-	s3Cleints = append(s3Cleints, s.s3)
+// bucketRegion returns the region of a bucket when it is not part of the listing
+func (s *s3Provider) bucketRegion(ctx context.Context, client *s3.Client, bucket, fallback string) string {
+	output, err := client.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return fallback
+	}
+	switch constraint := string(output.LocationConstraint); constraint {
+	case "":
+		return "us-east-1"
+	case "EU":
+		return "eu-west-1"
+	default:
+		return constraint
+	}
+}
 
-	if s.options.AssumeRoleName == "" || len(s.options.AccountIds) < 1 {
-		return s3Cleints
+// bucketPolicyPublic returns whether the bucket policy grants public access
+func bucketPolicyPublic(ctx context.Context, client *s3.Client, bucket string) (bool, bool) {
+	output, err := client.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return false, apiErrorCode(err) == "NoSuchBucketPolicy"
 	}
+	return output.PolicyStatus != nil && aws.ToBool(output.PolicyStatus.IsPublic), true
+}
 
-	for _, accountId := range s.options.AccountIds {
-		roleARN := fmt.Sprintf("arn:aws:iam::%s:role/%s", accountId, s.options.AssumeRoleName)
-		creds := stscreds.NewCredentials(s.session, roleARN)
+// publicACLGroups are the grantees that make an acl public
+var publicACLGroups = []string{
+	"http://acs.amazonaws.com/groups/global/AllUsers",
+	"http://acs.amazonaws.com/groups/global/AuthenticatedUsers",
+}
 
-		assumeSession, err := session.NewSession(&aws.Config{
-			Region:      aws.String("us-east-1"),
-			Credentials: creds,
-		})
-		if err != nil {
+// bucketACLPublic returns whether the bucket acl grants access to everyone
+func bucketACLPublic(ctx context.Context, client *s3.Client, bucket string) (bool, bool) {
+	output, err := client.GetBucketAcl(ctx, &s3.GetBucketAclInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return false, false
+	}
+	for _, grant := range output.Grants {
+		if grant.Grantee == nil || grant.Grantee.Type != types.TypeGroup {
 			continue
 		}
+		for _, group := range publicACLGroups {
+			if aws.ToString(grant.Grantee.URI) == group {
+				return true, true
+			}
+		}
+	}
+	return false, true
+}
+
+// accountPublicAccessBlock returns the block public access configuration of the account
+func accountPublicAccessBlock(ctx context.Context, cfg aws.Config, region string) publicAccessBlock {
+	identity, err := newClient(cfg, region, sts.NewFromConfig).GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return publicAccessBlock{}
+	}
+	output, err := newClient(cfg, region, s3control.NewFromConfig).GetPublicAccessBlock(ctx, &s3control.GetPublicAccessBlockInput{AccountId: identity.Account})
+	if err != nil || output.PublicAccessBlockConfiguration == nil {
+		return publicAccessBlock{}
+	}
+	config := output.PublicAccessBlockConfiguration
+	return publicAccessBlock{
+		blockPublicAcls:       aws.ToBool(config.BlockPublicAcls),
+		ignorePublicAcls:      aws.ToBool(config.IgnorePublicAcls),
+		blockPublicPolicy:     aws.ToBool(config.BlockPublicPolicy),
+		restrictPublicBuckets: aws.ToBool(config.RestrictPublicBuckets),
+	}
+}
+
+// s3DomainSuffix returns the domain of the s3 endpoints of a region
+func s3DomainSuffix(region string) string {
+	if strings.HasPrefix(region, "cn-") {
+		return "amazonaws.com.cn"
+	}
+	return "amazonaws.com"
+}
+
+// s3LegacyWebsiteRegions use the s3-website-<region> form of the website endpoint
+var s3LegacyWebsiteRegions = map[string]struct{}{
+	"us-east-1": {}, "us-west-1": {}, "us-west-2": {}, "us-gov-west-1": {}, "sa-east-1": {},
+	"eu-west-1": {}, "ap-southeast-1": {}, "ap-southeast-2": {}, "ap-northeast-1": {},
+}
+
+// s3WebsiteEndpoint returns the static website hosting endpoint of a bucket
+func s3WebsiteEndpoint(bucket, region string) string {
+	if _, ok := s3LegacyWebsiteRegions[region]; ok {
+		return fmt.Sprintf("%s.s3-website-%s.%s", bucket, region, s3DomainSuffix(region))
+	}
+	return fmt.Sprintf("%s.s3-website.%s.%s", bucket, region, s3DomainSuffix(region))
+}
 
-		s3Cleints = append(s3Cleints, s3.New(assumeSession))
+// apiErrorCode returns the error code of a failed aws api call
+func apiErrorCode(err error) string {
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
 	}
-	return s3Cleints
+	return ""
 }
@@ -0,0 +1,192 @@
+package azure
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice/v4"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/cloudlist/pkg/providers/k8s"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// aksServerScope is the scope of the tokens accepted by the api server of aad enabled clusters
+const aksServerScope = "6dae42f8-4368-4678-94ff-3960e28e3630/.default"
+
+// aksProvider is a provider for Azure Kubernetes Service clusters
+type aksProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+	// clusterCredentials is admin or aad when the clusters are walked
+	clusterCredentials string
+}
+
+func (ap *aksProvider) name() string {
+	return "aks"
+}
+
+// GetResource returns the api server endpoints of every cluster, along with
+// their services and ingresses when cluster credentials are configured.
+func (ap *aksProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	client, err := armcontainerservice.NewManagedClustersClient(ap.SubscriptionID, ap.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, cluster := range page.Value {
+			if cluster.Properties == nil {
+				continue
+			}
+			list.Merge(ap.endpointResources(cluster))
+
+			if ap.clusterCredentials == "" {
+				continue
+			}
+			clusterData, err := ap.clusterResources(ctx, client, cluster)
+			if err != nil {
+				gologger.Warning().Msgf("error listing resources of aks cluster %s: %s", deref(cluster.Name), err)
+				continue
+			}
+			list.Merge(clusterData)
+		}
+	}
+	return list, nil
+}
+
+// endpointResources returns the public and private api server fqdn of a cluster
+func (ap *aksProvider) endpointResources(cluster *armcontainerservice.ManagedCluster) *schema.Resources {
+	list := schema.NewResources()
+	properties := cluster.Properties
+
+	metadata := ap.clusterMetadata(cluster)
+	metadata["resource_type"] = "api_server"
+	private := false
+	if profile := properties.APIServerAccessProfile; profile != nil {
+		private = profile.EnablePrivateCluster != nil && *profile.EnablePrivateCluster
+		ranges := make([]string, 0, len(profile.AuthorizedIPRanges))
+		for _, ipRange := range profile.AuthorizedIPRanges {
+			ranges = append(ranges, deref(ipRange))
+		}
+		metadata["authorized_ip_ranges"] = strings.Join(ranges, ",")
+	}
+	if properties.PublicNetworkAccess != nil {
+		metadata["public_network_access"] = string(*properties.PublicNetworkAccess)
+	}
+	metadata["private_cluster"] = strconv.FormatBool(private)
+	metadata = cleanMetadata(metadata)
+
+	// private clusters may still publish a public fqdn resolving to the private address
+	if fqdn := deref(properties.Fqdn); fqdn != "" {
+		list.Append(&schema.Resource{
+			ID:       ap.id,
+			Public:   !private,
+			DNSName:  fqdn,
+			Provider: providerName,
+			Service:  ap.name(),
+			Metadata: metadata,
+		})
+	}
+	if fqdn := deref(properties.PrivateFQDN); fqdn != "" {
+		list.Append(&schema.Resource{
+			ID:       ap.id,
+			DNSName:  fqdn,
+			Provider: providerName,
+			Service:  ap.name(),
+			Metadata: metadata,
+		})
+	}
+	return list
+}
+
+// clusterResources walks the services and ingresses of a cluster through the kubernetes provider
+func (ap *aksProvider) clusterResources(ctx context.Context, client *armcontainerservice.ManagedClustersClient, cluster *armcontainerservice.ManagedCluster) (*schema.Resources, error) {
+	clientSet, err := ap.clusterClient(ctx, client, cluster)
+	if err != nil {
+		return nil, err
+	}
+	services := make(schema.ServiceMap)
+	for _, service := range k8s.Services {
+		services[service] = struct{}{}
+	}
+	list, err := k8s.ClusterResources(ctx, clientSet, ap.id, services)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list.Items {
+		metadata := ap.clusterMetadata(cluster)
+		metadata["resource_type"] = item.Service
+		item.Provider = providerName
+		item.Service = ap.name()
+		item.Metadata = cleanMetadata(metadata)
+	}
+	return list, nil
+}
+
+// clusterClient returns a kubernetes client built from the admin kubeconfig,
+// or from the user kubeconfig authenticated with an aad token of the credential
+func (ap *aksProvider) clusterClient(ctx context.Context, client *armcontainerservice.ManagedClustersClient, cluster *armcontainerservice.ManagedCluster) (*kubernetes.Clientset, error) {
+	res, err := arm.ParseResourceID(deref(cluster.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	var kubeconfigs []*armcontainerservice.CredentialResult
+	if ap.clusterCredentials == "admin" {
+		resp, err := client.ListClusterAdminCredentials(ctx, res.ResourceGroupName, res.Name, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get cluster admin credentials")
+		}
+		kubeconfigs = resp.Kubeconfigs
+	} else {
+		resp, err := client.ListClusterUserCredentials(ctx, res.ResourceGroupName, res.Name, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get cluster user credentials")
+		}
+		kubeconfigs = resp.Kubeconfigs
+	}
+	if len(kubeconfigs) == 0 {
+		return nil, errors.New("no kubeconfig returned for the cluster")
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfigs[0].Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse cluster kubeconfig")
+	}
+	if ap.clusterCredentials == "aad" {
+		// the user kubeconfig relies on kubelogin, the token is requested directly instead
+		token, err := ap.Credential.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{aksServerScope}})
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get aad token for the cluster")
+		}
+		config.ExecProvider = nil
+		config.AuthProvider = nil
+		config.BearerToken = token.Token
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+func (ap *aksProvider) clusterMetadata(cluster *armcontainerservice.ManagedCluster) map[string]string {
+	metadata := map[string]string{
+		"cluster":  deref(cluster.Name),
+		"location": deref(cluster.Location),
+	}
+	if cluster.Properties != nil {
+		metadata["kubernetes_version"] = deref(cluster.Properties.CurrentKubernetesVersion)
+	}
+	return metadata
+}
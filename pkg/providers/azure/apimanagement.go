@@ -0,0 +1,130 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// apiManagementAPIVersion is the Microsoft.ApiManagement api version used to list services
+const apiManagementAPIVersion = "2022-08-01"
+
+// apiManagementService is an Azure API Management instance
+type apiManagementService struct {
+	armResource
+	SKU struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+	Properties struct {
+		GatewayURL             string   `json:"gatewayUrl"`
+		PortalURL              string   `json:"portalUrl"`
+		DeveloperPortalURL     string   `json:"developerPortalUrl"`
+		ManagementAPIURL       string   `json:"managementApiUrl"`
+		ScmURL                 string   `json:"scmUrl"`
+		PublicIPAddresses      []string `json:"publicIPAddresses"`
+		PrivateIPAddresses     []string `json:"privateIPAddresses"`
+		VirtualNetworkType     string   `json:"virtualNetworkType"`
+		HostnameConfigurations []struct {
+			Type     string `json:"type"`
+			HostName string `json:"hostName"`
+		} `json:"hostnameConfigurations"`
+		AdditionalLocations []struct {
+			Location           string   `json:"location"`
+			GatewayRegionalURL string   `json:"gatewayRegionalUrl"`
+			PublicIPAddresses  []string `json:"publicIPAddresses"`
+		} `json:"additionalLocations"`
+	} `json:"properties"`
+}
+
+// apiManagementProvider is a provider for Azure API Management gateways and portals
+type apiManagementProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (amp *apiManagementProvider) name() string {
+	return "apimanagement"
+}
+
+// GetResource returns the gateway, portal and custom hostnames and the
+// addresses of every API Management service.
+func (amp *apiManagementProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	services, err := armList[apiManagementService](ctx, amp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.ApiManagement/service", amp.SubscriptionID), apiManagementAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range services {
+		properties := service.Properties
+		// internal services are only reachable from their virtual network
+		public := properties.VirtualNetworkType != "Internal"
+		newResource := func(resourceType, location string) *schema.Resource {
+			return &schema.Resource{
+				ID:       amp.id,
+				Public:   public,
+				Provider: providerName,
+				Service:  amp.name(),
+				Metadata: cleanMetadata(map[string]string{
+					"resource_type":        resourceType,
+					"name":                 service.Name,
+					"location":             location,
+					"sku":                  service.SKU.Name,
+					"virtual_network_type": properties.VirtualNetworkType,
+				}),
+			}
+		}
+
+		endpoints := [][2]string{
+			{"gateway", properties.GatewayURL},
+			{"portal", properties.PortalURL},
+			{"developer_portal", properties.DeveloperPortalURL},
+			{"management", properties.ManagementAPIURL},
+			{"scm", properties.ScmURL},
+		}
+		for _, endpoint := range endpoints {
+			parsed, err := url.Parse(endpoint[1])
+			if err != nil || parsed.Hostname() == "" {
+				continue
+			}
+			resource := newResource(endpoint[0], service.Location)
+			resource.DNSName = parsed.Hostname()
+			list.Append(resource)
+		}
+		for _, hostname := range properties.HostnameConfigurations {
+			resource := newResource("custom_domain", service.Location)
+			resource.DNSName = hostname.HostName
+			resource.Metadata["hostname_type"] = hostname.Type
+			list.Append(resource)
+		}
+		for _, ip := range properties.PublicIPAddresses {
+			resource := newResource("gateway", service.Location)
+			resource.PublicIPv4 = ip
+			list.Append(resource)
+		}
+		for _, ip := range properties.PrivateIPAddresses {
+			resource := newResource("gateway", service.Location)
+			resource.PrivateIpv4 = ip
+			list.Append(resource)
+		}
+
+		// multi-region deployments have a gateway per additional location
+		for _, location := range properties.AdditionalLocations {
+			if parsed, err := url.Parse(location.GatewayRegionalURL); err == nil && parsed.Hostname() != "" {
+				resource := newResource("regional_gateway", location.Location)
+				resource.DNSName = parsed.Hostname()
+				list.Append(resource)
+			}
+			for _, ip := range location.PublicIPAddresses {
+				resource := newResource("regional_gateway", location.Location)
+				resource.PublicIPv4 = ip
+				list.Append(resource)
+			}
+		}
+	}
+	return list, nil
+}
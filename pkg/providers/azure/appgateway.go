@@ -0,0 +1,144 @@
+package azure
+
+import (
+	"context"
+	"maps"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// appGatewayProvider is a provider for Azure Application Gateways
+type appGatewayProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (agp *appGatewayProvider) name() string {
+	return "appgateway"
+}
+
+// GetResource returns the frontend addresses and listener hostnames of every Application Gateway.
+func (agp *appGatewayProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	gateways, err := agp.fetchApplicationGateways(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(gateways) == 0 {
+		return list, nil
+	}
+	// frontends only reference their public ip, which are resolved from a single listing
+	publicIPs, err := (&publicIPProvider{SubscriptionID: agp.SubscriptionID, Credential: agp.Credential}).fetchPublicIPs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	publicIPsByID := make(map[string]*armnetwork.PublicIPAddress, len(publicIPs))
+	for _, ip := range publicIPs {
+		if ip.ID != nil {
+			publicIPsByID[strings.ToLower(*ip.ID)] = ip
+		}
+	}
+
+	for _, gateway := range gateways {
+		if gateway.Properties == nil {
+			continue
+		}
+		metadata := map[string]string{
+			"name":     deref(gateway.Name),
+			"location": deref(gateway.Location),
+		}
+		if gateway.Properties.SKU != nil && gateway.Properties.SKU.Tier != nil {
+			metadata["sku"] = string(*gateway.Properties.SKU.Tier)
+		}
+		if gateway.Properties.OperationalState != nil {
+			metadata["state"] = string(*gateway.Properties.OperationalState)
+		}
+
+		for _, frontend := range gateway.Properties.FrontendIPConfigurations {
+			if frontend.Properties == nil {
+				continue
+			}
+			frontendMetadata := maps.Clone(metadata)
+			frontendMetadata["resource_type"] = "frontend"
+			frontendMetadata["frontend"] = deref(frontend.Name)
+			resource := agp.resource(frontendMetadata)
+			resource.PrivateIpv4 = deref(frontend.Properties.PrivateIPAddress)
+			if ref := frontend.Properties.PublicIPAddress; ref != nil && ref.ID != nil {
+				if ip, ok := publicIPsByID[strings.ToLower(*ref.ID)]; ok && ip.Properties != nil {
+					resource.Public = true
+					resource.PublicIPv4 = deref(ip.Properties.IPAddress)
+					if ip.Properties.DNSSettings != nil {
+						resource.DNSName = deref(ip.Properties.DNSSettings.Fqdn)
+					}
+				}
+			}
+			list.Append(resource)
+		}
+
+		// hostnames of multi-site listeners are served by the gateway frontends
+		var hosts []string
+		for _, listener := range gateway.Properties.HTTPListeners {
+			if listener.Properties == nil {
+				continue
+			}
+			hosts = append(hosts, deref(listener.Properties.HostName))
+			for _, host := range listener.Properties.HostNames {
+				hosts = append(hosts, deref(host))
+			}
+		}
+		for _, listener := range gateway.Properties.Listeners {
+			if listener.Properties == nil {
+				continue
+			}
+			for _, host := range listener.Properties.HostNames {
+				hosts = append(hosts, deref(host))
+			}
+		}
+		for _, host := range hosts {
+			host = strings.TrimPrefix(host, "*.")
+			if host == "" {
+				continue
+			}
+			listenerMetadata := maps.Clone(metadata)
+			listenerMetadata["resource_type"] = "listener"
+			resource := agp.resource(listenerMetadata)
+			resource.Public = true
+			resource.DNSName = host
+			list.Append(resource)
+		}
+	}
+	return list, nil
+}
+
+func (agp *appGatewayProvider) resource(metadata map[string]string) *schema.Resource {
+	return &schema.Resource{
+		ID:       agp.id,
+		Provider: providerName,
+		Service:  agp.name(),
+		Metadata: cleanMetadata(metadata),
+	}
+}
+
+// fetchApplicationGateways retrieves all the Application Gateways of the subscription.
+func (agp *appGatewayProvider) fetchApplicationGateways(ctx context.Context) ([]*armnetwork.ApplicationGateway, error) {
+	client, err := armnetwork.NewApplicationGatewaysClient(agp.SubscriptionID, agp.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var gateways []*armnetwork.ApplicationGateway
+	pager := client.NewListAllPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		gateways = append(gateways, page.Value...)
+	}
+	return gateways, nil
+}
@@ -0,0 +1,127 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// appServiceAPIVersion is the Microsoft.Web api version used to list sites
+const appServiceAPIVersion = "2023-12-01"
+
+// webSite is an App Service web, api or function app, or a deployment slot
+type webSite struct {
+	armResource
+	Kind       string `json:"kind"`
+	Properties struct {
+		State               string   `json:"state"`
+		DefaultHostName     string   `json:"defaultHostName"`
+		EnabledHostNames    []string `json:"enabledHostNames"`
+		PublicNetworkAccess string   `json:"publicNetworkAccess"`
+		HostNameSslStates   []struct {
+			Name     string `json:"name"`
+			SslState string `json:"sslState"`
+			HostType string `json:"hostType"`
+		} `json:"hostNameSslStates"`
+	} `json:"properties"`
+}
+
+// appServiceProvider is a provider for Azure App Service sites and their custom domains
+type appServiceProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (asp *appServiceProvider) name() string {
+	return "appservice"
+}
+
+// GetResource returns all the App Service hostnames for a provider.
+func (asp *appServiceProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	sites, err := armList[webSite](ctx, asp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Web/sites", asp.SubscriptionID), appServiceAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, site := range sites {
+		list.Merge(asp.siteResources(&site, ""))
+
+		// deployment slots are served on their own hostnames
+		slots, err := armList[webSite](ctx, asp.Credential, site.ID+"/slots", appServiceAPIVersion)
+		if err != nil {
+			gologger.Warning().Msgf("error listing deployment slots of %s: %s", site.Name, err)
+			continue
+		}
+		for _, slot := range slots {
+			list.Merge(asp.siteResources(&slot, site.Name))
+		}
+	}
+	return list, nil
+}
+
+// graphResources returns the App Service hostnames of the sites and
+// deployment slots from resource graph
+func (asp *appServiceProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	sites, err := graphList[webSite](ctx, graph, "microsoft.web/sites", "microsoft.web/sites/slots")
+	if err != nil {
+		return nil, err
+	}
+	for _, site := range sites {
+		// deployment slots are named <site>/<slot>
+		parent, _, isSlot := strings.Cut(site.Name, "/")
+		if !isSlot {
+			parent = ""
+		}
+		list.Merge(asp.siteResources(&site, parent))
+	}
+	return list, nil
+}
+
+// siteResources returns the default and custom hostnames bound to a site
+func (asp *appServiceProvider) siteResources(site *webSite, parent string) *schema.Resources {
+	list := schema.NewResources()
+
+	sslStates := make(map[string]string)
+	hostTypes := make(map[string]string)
+	for _, state := range site.Properties.HostNameSslStates {
+		sslStates[state.Name] = state.SslState
+		hostTypes[state.Name] = state.HostType
+	}
+	// hostnames of the site outside of the default domain are custom domain bindings
+	defaultDomain := site.Properties.DefaultHostName[strings.Index(site.Properties.DefaultHostName, ".")+1:]
+
+	hosts := append([]string{site.Properties.DefaultHostName}, site.Properties.EnabledHostNames...)
+	for _, host := range hosts {
+		if host == "" {
+			continue
+		}
+		custom := !strings.HasSuffix(host, "."+defaultDomain)
+		list.Append(&schema.Resource{
+			ID:       asp.id,
+			Public:   !strings.EqualFold(site.Properties.PublicNetworkAccess, "Disabled"),
+			DNSName:  host,
+			Provider: providerName,
+			Service:  asp.name(),
+			Metadata: cleanMetadata(map[string]string{
+				"name":           site.Name,
+				"parent_site":    parent,
+				"kind":           site.Kind,
+				"location":       site.Location,
+				"state":          site.Properties.State,
+				"host_type":      strings.ToLower(hostTypes[host]),
+				"ssl_state":      sslStates[host],
+				"custom_domain":  fmt.Sprint(custom),
+				"network_access": site.Properties.PublicNetworkAccess,
+			}),
+		})
+	}
+	return list
+}
@@ -0,0 +1,90 @@
+package azure
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+)
+
+// armResource holds the fields shared by every resource manager resource
+type armResource struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Location string `json:"location"`
+}
+
+// armList pages through a resource manager list operation, the value of
+// every page being decoded into T. It is used for the resource providers
+// that have no sdk module, path is relative to the resource manager
+// endpoint (e.g. /subscriptions/<id>/providers/Microsoft.Web/sites).
+func armList[T any](ctx context.Context, credential azcore.TokenCredential, path, apiVersion string) ([]T, error) {
+	client, err := arm.NewClient("cloudlist", "", credential, &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{Telemetry: policy.TelemetryOptions{Disabled: true}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var items []T
+	next := runtime.JoinPaths(client.Endpoint(), path) + "?api-version=" + apiVersion
+	for next != "" {
+		req, err := runtime.NewRequest(ctx, http.MethodGet, next)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := client.Pipeline().Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if !runtime.HasStatusCode(resp, http.StatusOK) {
+			return nil, runtime.NewResponseError(resp)
+		}
+		var page struct {
+			Value    []T    `json:"value"`
+			NextLink string `json:"nextLink"`
+		}
+		if err := runtime.UnmarshalAsJSON(resp, &page); err != nil {
+			return nil, err
+		}
+		items = append(items, page.Value...)
+		next = page.NextLink
+	}
+	return items, nil
+}
+
+// cleanMetadata removes the empty values from the metadata of a resource
+func cleanMetadata(metadata map[string]string) map[string]string {
+	for key, value := range metadata {
+		if value == "" {
+			delete(metadata, key)
+		}
+	}
+	return metadata
+}
+
+// deref returns the value of an optional string of the sdk models
+func deref(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// networkExposure returns disabled, restricted or open depending on the
+// public network access setting and firewall of a service
+func networkExposure(publicNetworkAccess string, restricted bool) string {
+	switch {
+	case strings.EqualFold(publicNetworkAccess, "Disabled"):
+		return "disabled"
+	case restricted:
+		return "restricted"
+	default:
+		return "open"
+	}
+}
@@ -0,0 +1,224 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/managementgroups/armmanagementgroups"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+)
+
+// newCredential returns the token credential for the auth method configured
+// in the option block.
+//
+// Explicit methods are checked first (cli, managed identity, workload identity,
+// client certificate, client secret). If none is configured the default
+// credential chain is used, which reads the standard AZURE_* environment
+// variables and falls back to workload identity, managed identity and the cli.
+func newCredential(options schema.OptionBlock) (azcore.TokenCredential, error) {
+	ClientID, _ := options.GetMetadata(clientID)
+	TenantID, _ := options.GetMetadata(tenantID)
+
+	if isEnabled(options, useCliAuth) {
+		return azidentity.NewAzureCLICredential(&azidentity.AzureCLICredentialOptions{TenantID: TenantID})
+	}
+
+	if isEnabled(options, useManagedIdentity) {
+		miOptions := &azidentity.ManagedIdentityCredentialOptions{}
+		// client_id selects a user-assigned identity, system-assigned otherwise
+		if ClientID != "" {
+			miOptions.ID = azidentity.ClientID(ClientID)
+		}
+		return azidentity.NewManagedIdentityCredential(miOptions)
+	}
+
+	if isEnabled(options, useWorkloadIdentity) {
+		wiOptions := &azidentity.WorkloadIdentityCredentialOptions{
+			ClientID: ClientID,
+			TenantID: TenantID,
+		}
+		wiOptions.TokenFilePath, _ = options.GetMetadata(federatedTokenFile)
+		return azidentity.NewWorkloadIdentityCredential(wiOptions)
+	}
+
+	if certificatePath, ok := options.GetMetadata(clientCertificatePath); ok {
+		if ClientID == "" {
+			return nil, &schema.ErrNoSuchKey{Name: clientID}
+		}
+		if TenantID == "" {
+			return nil, &schema.ErrNoSuchKey{Name: tenantID}
+		}
+		data, err := os.ReadFile(certificatePath)
+		if err != nil {
+			return nil, fmt.Errorf("could not read client certificate: %w", err)
+		}
+		var password []byte
+		if value, ok := options.GetMetadata(clientCertificatePassword); ok {
+			password = []byte(value)
+		}
+		certs, key, err := azidentity.ParseCertificates(data, password)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse client certificate: %w", err)
+		}
+		return azidentity.NewClientCertificateCredential(TenantID, ClientID, certs, key, nil)
+	}
+
+	if ClientSecret, ok := options.GetMetadata(clientSecret); ok {
+		if ClientID == "" {
+			return nil, &schema.ErrNoSuchKey{Name: clientID}
+		}
+		if TenantID == "" {
+			return nil, &schema.ErrNoSuchKey{Name: tenantID}
+		}
+		return azidentity.NewClientSecretCredential(TenantID, ClientID, ClientSecret, nil)
+	}
+
+	gologger.Verbose().Msgf("No explicit azure auth method configured, using default credential chain")
+	return azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{TenantID: TenantID})
+}
+
+// subscriptionScope describes which subscriptions the provider enumerates.
+type subscriptionScope struct {
+	include           []string
+	exclude           []string
+	managementGroupID string
+}
+
+func parseSubscriptionScope(options schema.OptionBlock) *subscriptionScope {
+	scope := &subscriptionScope{}
+	if value, ok := options.GetMetadata(subscriptionID); ok {
+		scope.include = append(scope.include, splitList(value)...)
+	}
+	if value, ok := options.GetMetadata(subscriptionIDs); ok {
+		scope.include = append(scope.include, splitList(value)...)
+	}
+	if value, ok := options.GetMetadata(excludeSubscriptionIDs); ok {
+		scope.exclude = splitList(value)
+	}
+	scope.managementGroupID, _ = options.GetMetadata(managementGroupID)
+	scope.include = sliceutil.Dedupe(scope.include)
+	return scope
+}
+
+// resolveSubscriptions returns the subscription ids to enumerate for the scope
+func (s *subscriptionScope) resolveSubscriptions(ctx context.Context, credential azcore.TokenCredential) ([]string, error) {
+	var subIDs []string
+	var err error
+
+	switch {
+	case len(s.include) > 0 && s.managementGroupID == "":
+		subIDs = s.include
+	case s.managementGroupID != "":
+		gologger.Info().Msgf("Listing subscriptions of management group %s from provider: azure", s.managementGroupID)
+		subIDs, err = listManagementGroupSubscriptions(ctx, credential, s.managementGroupID)
+		if err != nil {
+			return nil, err
+		}
+		// explicit subscriptions narrow down the management group
+		if len(s.include) > 0 {
+			subIDs = filterSubscriptions(subIDs, s.include, true)
+		}
+	default:
+		gologger.Info().Msgf("Listing subscriptions from provider: azure")
+		subIDs, err = listSubscriptions(ctx, credential)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return filterSubscriptions(subIDs, s.exclude, false), nil
+}
+
+// filterSubscriptions keeps (or drops) the subscriptions present in the list
+func filterSubscriptions(subIDs, list []string, keep bool) []string {
+	var filtered []string
+	for _, subID := range subIDs {
+		found := false
+		for _, item := range list {
+			if strings.EqualFold(item, subID) {
+				found = true
+				break
+			}
+		}
+		if found != keep {
+			gologger.Verbose().Msgf("Skipping subscription: %s", subID)
+			continue
+		}
+		filtered = append(filtered, subID)
+	}
+	return filtered
+}
+
+// listSubscriptions lists all the subscriptions visible to the credential
+func listSubscriptions(ctx context.Context, credential azcore.TokenCredential) ([]string, error) {
+	client, err := armsubscriptions.NewClient(credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create subscriptions client: %v", err)
+	}
+
+	var subIDs []string
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list subscriptions: %v", err)
+		}
+		for _, sub := range page.Value {
+			if sub.SubscriptionID != nil {
+				subIDs = append(subIDs, *sub.SubscriptionID)
+				gologger.Info().Msgf("Discovered subscription: %s", *sub.SubscriptionID)
+			}
+		}
+	}
+	return subIDs, nil
+}
+
+// listManagementGroupSubscriptions lists all the subscriptions below a
+// management group, including those of nested management groups.
+func listManagementGroupSubscriptions(ctx context.Context, credential azcore.TokenCredential, groupID string) ([]string, error) {
+	client, err := armmanagementgroups.NewClient(credential, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create management groups client: %v", err)
+	}
+
+	var subIDs []string
+	pager := client.NewGetDescendantsPager(groupID, nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list descendants of management group %s: %v", groupID, err)
+		}
+		for _, descendant := range page.Value {
+			if descendant.Type == nil || descendant.Name == nil {
+				continue
+			}
+			if strings.HasSuffix(strings.ToLower(*descendant.Type), "/subscriptions") {
+				subIDs = append(subIDs, *descendant.Name)
+				gologger.Info().Msgf("Discovered subscription: %s", *descendant.Name)
+			}
+		}
+	}
+	return subIDs, nil
+}
+
+func isEnabled(options schema.OptionBlock, key string) bool {
+	value, _ := options.GetMetadata(key)
+	return strings.EqualFold(value, "true")
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
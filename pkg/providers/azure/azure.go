@@ -4,71 +4,58 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/resources/mgmt/resources"
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/resources/mgmt/subscriptions"
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/trafficmanager/mgmt/trafficmanager"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
+	"github.com/alitto/pond/v2"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	"github.com/projectdiscovery/gologger"
 )
 
 const (
-	id             = `id`
-	tenantID       = `tenant_id`
-	clientID       = `client_id`
-	clientSecret   = `client_secret`
-	subscriptionID = `subscription_id` // optional
-	useCliAuth     = `use_cli_auth`
+	id                        = `id`
+	tenantID                  = `tenant_id`
+	clientID                  = `client_id`
+	clientSecret              = `client_secret`
+	clientCertificatePath     = `client_certificate_path`
+	clientCertificatePassword = `client_certificate_password`
+	useCliAuth                = `use_cli_auth`
+	useManagedIdentity        = `use_managed_identity`
+	useWorkloadIdentity       = `use_workload_identity`
+	federatedTokenFile        = `federated_token_file`
+	subscriptionID            = `subscription_id`          // optional
+	subscriptionIDs           = `subscription_ids`         // optional
+	excludeSubscriptionIDs    = `exclude_subscription_ids` // optional
+	managementGroupID         = `management_group_id`      // optional
+	aksCredentials            = `aks_credentials`          // optional
+	useResourceGraph          = `use_resource_graph`       // optional
 
 	providerName = "azure"
 )
 
-var Services = []string{"vm", "publicip", "trafficmanager"}
+var Services = []string{"vm", "publicip", "trafficmanager", "appservice", "frontdoor", "appgateway", "dns", "aks", "containerapps", "containerinstances", "apimanagement", "storage", "sql", "cosmosdb", "keyvault"}
 
 // Provider is a data provider for Azure API
 type Provider struct {
 	id              string
 	SubscriptionIDs []string
-	Authorizer      autorest.Authorizer
+	Credential      azcore.TokenCredential
 	services        schema.ServiceMap
+	aksCredentials  string
+	resourceGraph   bool
 }
 
 // New creates a new provider client for Azure API
 func New(options schema.OptionBlock) (*Provider, error) {
 	ID, _ := options.GetMetadata(id)
-	UseCliAuth, _ := options.GetMetadata(useCliAuth)
 
-	var authorizer autorest.Authorizer
-	var err error
-
-	if UseCliAuth == "true" {
-		authorizer, err = auth.NewAuthorizerFromCLI()
-		if err != nil {
-			gologger.Error().Msgf("Couldn't authorize using cli: %s\n", err)
-			return nil, err
-		}
-	} else {
-		ClientID, ok := options.GetMetadata(clientID)
-		if !ok {
-			return nil, &schema.ErrNoSuchKey{Name: clientID}
-		}
-		ClientSecret, ok := options.GetMetadata(clientSecret)
-		if !ok {
-			return nil, &schema.ErrNoSuchKey{Name: clientSecret}
-		}
-		TenantID, ok := options.GetMetadata(tenantID)
-		if !ok {
-			return nil, &schema.ErrNoSuchKey{Name: tenantID}
-		}
-
-		config := auth.NewClientCredentialsConfig(ClientID, ClientSecret, TenantID)
-		authorizer, err = config.Authorizer()
-		if err != nil {
-			return nil, err
-		}
+	credential, err := newCredential(options)
+	if err != nil {
+		gologger.Error().Msgf("Couldn't create azure credential: %s\n", err)
+		return nil, err
 	}
 
 	// Parse services
@@ -91,41 +78,22 @@ func New(options schema.OptionBlock) (*Provider, error) {
 	}
 
 	provider := &Provider{
-		Authorizer: authorizer,
-		id:         ID,
-		services:   services,
-	}
-
-	// Check if a specific subscription ID was provided
-	specifiedSubID, hasSpecificSub := options.GetMetadata(subscriptionID)
-
-	// If a specific subscription was provided, use only that one
-	if hasSpecificSub && specifiedSubID != "" {
-		provider.SubscriptionIDs = []string{specifiedSubID}
-		return provider, nil
+		Credential:    credential,
+		id:            ID,
+		services:      services,
+		resourceGraph: isEnabled(options, useResourceGraph),
 	}
-
-	// Otherwise, discover all available subscriptions
-	gologger.Info().Msgf("Listing subscriptions from provider: azure")
-
-	ctx := context.Background()
-	subsClient := subscriptions.NewClient()
-	subsClient.Authorizer = authorizer
-
-	var subIDs []string
-	for subsList, err := subsClient.List(ctx); subsList.NotDone(); err = subsList.NextWithContext(ctx) {
-		if err != nil {
-			return nil, fmt.Errorf("failed to list subscriptions: %v", err)
-		}
-
-		for _, sub := range subsList.Values() {
-			if sub.SubscriptionID != nil {
-				subIDs = append(subIDs, *sub.SubscriptionID)
-				gologger.Info().Msgf("Discovered subscription: %s", *sub.SubscriptionID)
-			}
+	if value, ok := options.GetMetadata(aksCredentials); ok {
+		if value != "admin" && value != "aad" {
+			return nil, fmt.Errorf("invalid aks_credentials %q, expected admin or aad", value)
 		}
+		provider.aksCredentials = value
 	}
 
+	subIDs, err := parseSubscriptionScope(options).resolveSubscriptions(context.Background(), credential)
+	if err != nil {
+		return nil, err
+	}
 	if len(subIDs) == 0 {
 		return nil, fmt.Errorf("no subscriptions found for the provided credentials")
 	}
@@ -149,83 +117,122 @@ func (p *Provider) Services() []string {
 	return p.services.Keys()
 }
 
+// subscriptionConcurrency is the number of subscriptions enumerated at once
+const subscriptionConcurrency = 8
+
+// serviceProvider is implemented by the provider of every azure service
+type serviceProvider interface {
+	name() string
+	GetResource(ctx context.Context) (*schema.Resources, error)
+}
+
 // Resources returns the provider for an resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 	resources := schema.NewResources()
+	mu := &sync.Mutex{}
+
+	services := p.services
+	if p.resourceGraph {
+		graphResources, remaining, err := p.resourceGraphResources(ctx)
+		if err != nil {
+			gologger.Warning().Msgf("Could not use resource graph, falling back to the resource provider apis: %s", err)
+		} else {
+			resources.Merge(graphResources)
+			services = remaining
+		}
+	}
+	if len(services) == 0 {
+		return resources, nil
+	}
 
-	// Process each subscription
+	pool := pond.NewPool(subscriptionConcurrency, pond.WithContext(ctx))
 	for _, subscriptionID := range p.SubscriptionIDs {
-		gologger.Info().Msgf("Processing subscription: %s", subscriptionID)
+		subscriptionID := subscriptionID
 
-		if p.services.Has("vm") {
-			vmp := &vmProvider{Authorizer: p.Authorizer, SubscriptionID: subscriptionID, id: p.id}
-			vmIPs, err := vmp.GetResource(ctx)
-			if err != nil {
-				gologger.Warning().Msgf("Error listing VM public IPs for subscription %s: %s", subscriptionID, err)
-				continue
-			}
-			resources.Merge(vmIPs)
-		}
+		pool.Submit(func() {
+			subscriptionResources := p.subscriptionResources(ctx, subscriptionID, services)
 
-		if p.services.Has("publicip") {
-			publicIPp := &publicIPProvider{Authorizer: p.Authorizer, SubscriptionID: subscriptionID, id: p.id}
-			publicIPs, err := publicIPp.GetResource(ctx)
-			if err != nil {
-				gologger.Warning().Msgf("Error listing public IPs for subscription %s: %s", subscriptionID, err)
-				continue
-			}
-			resources.Merge(publicIPs)
-		}
+			mu.Lock()
+			resources.Merge(subscriptionResources)
+			mu.Unlock()
+		})
+	}
+	pool.StopAndWait()
 
-		if p.services.Has("trafficmanager") {
-			trafficManagerp := &trafficManagerProvider{Authorizer: p.Authorizer, SubscriptionID: subscriptionID, id: p.id}
-			trafficManager, err := trafficManagerp.GetResource(ctx)
-			if err != nil {
-				gologger.Warning().Msgf("Error listing traffic manager for subscription %s: %s", subscriptionID, err)
-				continue
-			}
-			resources.Merge(trafficManager)
-		}
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 	return resources, nil
 }
 
-// Verify checks if the provider is valid using simple API call
-func (p *Provider) Verify(ctx context.Context) error {
-	for _, subscriptionID := range p.SubscriptionIDs {
-		groupsClient := resources.NewGroupsClient(subscriptionID)
-		groupsClient.Authorizer = p.Authorizer
+// subscriptionResources returns the resources of the given services of a
+// subscription, a failing service does not stop the others from being listed
+func (p *Provider) subscriptionResources(ctx context.Context, subscriptionID string, services schema.ServiceMap) *schema.Resources {
+	gologger.Info().Msgf("Processing subscription: %s", subscriptionID)
 
-		pClient := network.NewPublicIPAddressesClient(subscriptionID)
-		pClient.Authorizer = p.Authorizer
+	resources := schema.NewResources()
+	for _, service := range p.serviceProviders(subscriptionID) {
+		if !services.Has(service.name()) {
+			continue
+		}
+		serviceResources, err := service.GetResource(ctx)
+		if err != nil {
+			gologger.Warning().Msgf("Error listing %s resources for subscription %s: %s", service.name(), subscriptionID, err)
+			continue
+		}
+		resources.Merge(serviceResources)
+	}
+	return resources
+}
 
-		trafficManagerClient := trafficmanager.NewProfilesClient(subscriptionID)
-		trafficManagerClient.Authorizer = p.Authorizer
+// serviceProviders returns the providers of every service for a subscription
+func (p *Provider) serviceProviders(subscriptionID string) []serviceProvider {
+	return []serviceProvider{
+		&vmProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&publicIPProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&trafficManagerProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&appServiceProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&frontDoorProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&appGatewayProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&dnsProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&aksProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id, clusterCredentials: p.aksCredentials},
+		&containerAppsProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&containerInstancesProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&apiManagementProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&storageProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&sqlProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&cosmosDBProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+		&keyVaultProvider{Credential: p.Credential, SubscriptionID: subscriptionID, id: p.id},
+	}
+}
 
-		// Try a lightweight operation - just list the first group
-		var success bool
+// Verify checks if the provider is valid using simple API call
+func (p *Provider) Verify(ctx context.Context) error {
+	for _, subscriptionID := range p.SubscriptionIDs {
+		// Try a lightweight operation - just fetch the first page
+		var err error
 		if p.services.Has("vm") {
-			_, err := groupsClient.List(ctx, "", nil)
-			if err != nil {
-				return fmt.Errorf("failed to verify Azure credentials: %v", err)
+			var groupsClient *armresources.ResourceGroupsClient
+			if groupsClient, err = armresources.NewResourceGroupsClient(subscriptionID, p.Credential, nil); err == nil {
+				_, err = groupsClient.NewListPager(nil).NextPage(ctx)
 			}
-			success = true
-		} else if p.services.Has("publicip") && !success {
-			_, err := pClient.ListAllComplete(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to verify Azure credentials: %v", err)
+		} else if p.services.Has("publicip") {
+			var ipClient *armnetwork.PublicIPAddressesClient
+			if ipClient, err = armnetwork.NewPublicIPAddressesClient(subscriptionID, p.Credential, nil); err == nil {
+				_, err = ipClient.NewListAllPager(nil).NextPage(ctx)
 			}
-			success = true
-		} else if p.services.Has("trafficmanager") && !success {
-			_, err := trafficManagerClient.ListBySubscription(ctx)
-			if err != nil {
-				return fmt.Errorf("failed to verify Azure credentials: %v", err)
+		} else if p.services.Has("trafficmanager") {
+			var profilesClient *armtrafficmanager.ProfilesClient
+			if profilesClient, err = armtrafficmanager.NewProfilesClient(subscriptionID, p.Credential, nil); err == nil {
+				_, err = profilesClient.NewListBySubscriptionPager(nil).NextPage(ctx)
 			}
-			success = true
+		} else {
+			continue
 		}
-		if success {
-			return nil
+		if err != nil {
+			return fmt.Errorf("failed to verify Azure credentials: %v", err)
 		}
+		return nil
 	}
 	return fmt.Errorf("no accessible Azure services found with provided credentials")
 }
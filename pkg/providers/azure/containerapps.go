@@ -0,0 +1,124 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// containerAppsAPIVersion is the Microsoft.App api version used to list apps and environments
+const containerAppsAPIVersion = "2024-03-01"
+
+// containerApp is an Azure Container App
+type containerApp struct {
+	armResource
+	Properties struct {
+		LatestRevisionFqdn string `json:"latestRevisionFqdn"`
+		Configuration      struct {
+			Ingress *struct {
+				Fqdn          string `json:"fqdn"`
+				External      bool   `json:"external"`
+				TargetPort    int    `json:"targetPort"`
+				CustomDomains []struct {
+					Name string `json:"name"`
+				} `json:"customDomains"`
+			} `json:"ingress"`
+		} `json:"configuration"`
+	} `json:"properties"`
+}
+
+// managedEnvironment is the environment Container Apps are deployed in
+type managedEnvironment struct {
+	armResource
+	Properties struct {
+		DefaultDomain string `json:"defaultDomain"`
+		StaticIP      string `json:"staticIp"`
+		VnetConfig    *struct {
+			Internal bool `json:"internal"`
+		} `json:"vnetConfiguration"`
+	} `json:"properties"`
+}
+
+// containerAppsProvider is a provider for Azure Container Apps
+type containerAppsProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (cp *containerAppsProvider) name() string {
+	return "containerapps"
+}
+
+// GetResource returns the ingress hostnames of every Container App and the
+// static address of every environment.
+func (cp *containerAppsProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	apps, err := armList[containerApp](ctx, cp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.App/containerApps", cp.SubscriptionID), containerAppsAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, app := range apps {
+		ingress := app.Properties.Configuration.Ingress
+		if ingress == nil {
+			continue
+		}
+		metadata := map[string]string{
+			"resource_type":    "container_app",
+			"name":             app.Name,
+			"location":         app.Location,
+			"external_ingress": strconv.FormatBool(ingress.External),
+			"target_port":      strconv.Itoa(ingress.TargetPort),
+		}
+		list.Append(cp.hostResource(ingress.Fqdn, ingress.External, metadata, false))
+		for _, domain := range ingress.CustomDomains {
+			list.Append(cp.hostResource(domain.Name, ingress.External, metadata, true))
+		}
+	}
+
+	environments, err := armList[managedEnvironment](ctx, cp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.App/managedEnvironments", cp.SubscriptionID), containerAppsAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, environment := range environments {
+		internal := environment.Properties.VnetConfig != nil && environment.Properties.VnetConfig.Internal
+		resource := &schema.Resource{
+			ID:       cp.id,
+			Provider: providerName,
+			Service:  cp.name(),
+			Metadata: cleanMetadata(map[string]string{
+				"resource_type":  "environment",
+				"name":           environment.Name,
+				"location":       environment.Location,
+				"default_domain": environment.Properties.DefaultDomain,
+				"internal":       strconv.FormatBool(internal),
+			}),
+		}
+		if internal {
+			resource.PrivateIpv4 = environment.Properties.StaticIP
+		} else {
+			resource.Public = true
+			resource.PublicIPv4 = environment.Properties.StaticIP
+		}
+		list.Append(resource)
+	}
+	return list, nil
+}
+
+func (cp *containerAppsProvider) hostResource(host string, public bool, metadata map[string]string, custom bool) *schema.Resource {
+	resourceMetadata := maps.Clone(metadata)
+	resourceMetadata["custom_domain"] = strconv.FormatBool(custom)
+	return &schema.Resource{
+		ID:       cp.id,
+		Public:   public,
+		DNSName:  host,
+		Provider: providerName,
+		Service:  cp.name(),
+		Metadata: cleanMetadata(resourceMetadata),
+	}
+}
@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// containerInstancesAPIVersion is the Microsoft.ContainerInstance api version used to list container groups
+const containerInstancesAPIVersion = "2023-05-01"
+
+// containerGroup is an Azure Container Instances container group
+type containerGroup struct {
+	armResource
+	Properties struct {
+		IPAddress *struct {
+			IP           string `json:"ip"`
+			Type         string `json:"type"`
+			Fqdn         string `json:"fqdn"`
+			DNSNameLabel string `json:"dnsNameLabel"`
+			Ports        []struct {
+				Port     int    `json:"port"`
+				Protocol string `json:"protocol"`
+			} `json:"ports"`
+		} `json:"ipAddress"`
+	} `json:"properties"`
+}
+
+// containerInstancesProvider is a provider for Azure Container Instances
+type containerInstancesProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (cip *containerInstancesProvider) name() string {
+	return "containerinstances"
+}
+
+// GetResource returns the address and dns label of every container group.
+func (cip *containerInstancesProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	groups, err := armList[containerGroup](ctx, cip.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.ContainerInstance/containerGroups", cip.SubscriptionID), containerInstancesAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, group := range groups {
+		address := group.Properties.IPAddress
+		if address == nil {
+			continue
+		}
+		ports := make([]string, 0, len(address.Ports))
+		for _, port := range address.Ports {
+			ports = append(ports, strconv.Itoa(port.Port)+"/"+strings.ToLower(port.Protocol))
+		}
+		resource := &schema.Resource{
+			ID:       cip.id,
+			DNSName:  address.Fqdn,
+			Provider: providerName,
+			Service:  cip.name(),
+			Metadata: cleanMetadata(map[string]string{
+				"name":           group.Name,
+				"location":       group.Location,
+				"dns_name_label": address.DNSNameLabel,
+				"ports":          strings.Join(ports, ","),
+			}),
+		}
+		if strings.EqualFold(address.Type, "Private") {
+			resource.PrivateIpv4 = address.IP
+		} else {
+			resource.Public = true
+			resource.PublicIPv4 = address.IP
+		}
+		list.Append(resource)
+	}
+	return list, nil
+}
@@ -0,0 +1,82 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// cosmosDBAPIVersion is the Microsoft.DocumentDB api version used to list database accounts
+const cosmosDBAPIVersion = "2023-04-15"
+
+// cosmosDBAccount is an Azure Cosmos DB database account
+type cosmosDBAccount struct {
+	armResource
+	Kind       string `json:"kind"`
+	Properties struct {
+		DocumentEndpoint              string `json:"documentEndpoint"`
+		PublicNetworkAccess           string `json:"publicNetworkAccess"`
+		IsVirtualNetworkFilterEnabled bool   `json:"isVirtualNetworkFilterEnabled"`
+		IPRules                       []struct {
+			IPAddressOrRange string `json:"ipAddressOrRange"`
+		} `json:"ipRules"`
+		Locations []struct {
+			LocationName     string `json:"locationName"`
+			DocumentEndpoint string `json:"documentEndpoint"`
+		} `json:"locations"`
+	} `json:"properties"`
+}
+
+// cosmosDBProvider is a provider for Azure Cosmos DB accounts
+type cosmosDBProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (cp *cosmosDBProvider) name() string {
+	return "cosmosdb"
+}
+
+// GetResource returns the global and regional endpoints of every Cosmos DB account.
+func (cp *cosmosDBProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	accounts, err := armList[cosmosDBAccount](ctx, cp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.DocumentDB/databaseAccounts", cp.SubscriptionID), cosmosDBAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		properties := account.Properties
+		restricted := properties.IsVirtualNetworkFilterEnabled || len(properties.IPRules) > 0
+		exposure := networkExposure(properties.PublicNetworkAccess, restricted)
+
+		endpoints := []string{properties.DocumentEndpoint}
+		for _, location := range properties.Locations {
+			endpoints = append(endpoints, location.DocumentEndpoint)
+		}
+		for _, endpoint := range endpoints {
+			parsed, err := url.Parse(endpoint)
+			if err != nil || parsed.Hostname() == "" {
+				continue
+			}
+			list.Append(&schema.Resource{
+				ID:       cp.id,
+				Public:   exposure != "disabled",
+				DNSName:  parsed.Hostname(),
+				Provider: providerName,
+				Service:  cp.name(),
+				Metadata: cleanMetadata(map[string]string{
+					"name":           account.Name,
+					"location":       account.Location,
+					"kind":           account.Kind,
+					"network_access": exposure,
+				}),
+			})
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,156 @@
+package azure
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/dns/armdns"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// dnsProvider is a provider for Azure DNS zones
+type dnsProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (dp *dnsProvider) name() string {
+	return "dns"
+}
+
+// GetResource returns the address and cname records of every public DNS zone.
+func (dp *dnsProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	zonesClient, err := armdns.NewZonesClient(dp.SubscriptionID, dp.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+	recordSetsClient, err := armdns.NewRecordSetsClient(dp.SubscriptionID, dp.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	pager := zonesClient.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, zone := range page.Value {
+			list.Merge(dp.zoneResources(ctx, recordSetsClient, zone))
+		}
+	}
+	return list, nil
+}
+
+// graphResources returns the records of the DNS zones found with resource
+// graph, record sets are not indexed by resource graph so they are still
+// listed from the zones of each subscription
+func (dp *dnsProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	zones, err := graphList[*armdns.Zone](ctx, graph, "microsoft.network/dnszones")
+	if err != nil {
+		return nil, err
+	}
+	recordSetsClients := make(map[string]*armdns.RecordSetsClient)
+	for _, zone := range zones {
+		if zone.ID == nil {
+			continue
+		}
+		res, err := arm.ParseResourceID(*zone.ID)
+		if err != nil {
+			gologger.Warning().Msgf("error parsing resource ID: %s", err)
+			continue
+		}
+		recordSetsClient, ok := recordSetsClients[res.SubscriptionID]
+		if !ok {
+			if recordSetsClient, err = armdns.NewRecordSetsClient(res.SubscriptionID, graph.credential, nil); err != nil {
+				return nil, err
+			}
+			recordSetsClients[res.SubscriptionID] = recordSetsClient
+		}
+		list.Merge(dp.zoneResources(ctx, recordSetsClient, zone))
+	}
+	return list, nil
+}
+
+// zoneResources returns the records of a zone
+func (dp *dnsProvider) zoneResources(ctx context.Context, recordSetsClient *armdns.RecordSetsClient, zone *armdns.Zone) *schema.Resources {
+	list := schema.NewResources()
+	if zone.ID == nil || zone.Name == nil {
+		return list
+	}
+	res, err := arm.ParseResourceID(*zone.ID)
+	if err != nil {
+		gologger.Warning().Msgf("error parsing resource ID: %s", err)
+		return list
+	}
+
+	recordPager := recordSetsClient.NewListAllByDNSZonePager(res.ResourceGroupName, *zone.Name, nil)
+	for recordPager.More() {
+		recordPage, err := recordPager.NextPage(ctx)
+		if err != nil {
+			gologger.Warning().Msgf("error listing records of zone %s: %s", *zone.Name, err)
+			break
+		}
+		for _, recordSet := range recordPage.Value {
+			list.Merge(dp.recordSetResources(*zone.Name, recordSet))
+		}
+	}
+	return list
+}
+
+// recordSetResources returns every value of an A, AAAA or CNAME record set
+func (dp *dnsProvider) recordSetResources(zone string, recordSet *armdns.RecordSet) *schema.Resources {
+	list := schema.NewResources()
+	if recordSet.Properties == nil || recordSet.Type == nil {
+		return list
+	}
+	recordType := (*recordSet.Type)[strings.LastIndex(*recordSet.Type, "/")+1:]
+	if recordType != "A" && recordType != "AAAA" && recordType != "CNAME" {
+		return list
+	}
+
+	properties := recordSet.Properties
+	metadata := map[string]string{
+		"zone":        zone,
+		"record_type": recordType,
+	}
+	if properties.CnameRecord != nil {
+		metadata["cname"] = deref(properties.CnameRecord.Cname)
+	}
+	// alias record sets point to an azure resource instead of holding values
+	if properties.TargetResource != nil {
+		metadata["alias_target"] = deref(properties.TargetResource.ID)
+	}
+	metadata = cleanMetadata(metadata)
+
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			ID:       dp.id,
+			Public:   true,
+			DNSName:  strings.TrimSuffix(deref(properties.Fqdn), "."),
+			Provider: providerName,
+			Service:  dp.name(),
+			Metadata: metadata,
+		}
+	}
+	list.Append(newResource())
+	for _, record := range properties.ARecords {
+		resource := newResource()
+		resource.PublicIPv4 = deref(record.IPv4Address)
+		list.Append(resource)
+	}
+	for _, record := range properties.AaaaRecords {
+		resource := newResource()
+		resource.PublicIPv6 = deref(record.IPv6Address)
+		list.Append(resource)
+	}
+	return list
+}
@@ -0,0 +1,183 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+const (
+	// cdnAPIVersion is the Microsoft.Cdn api version used for front door standard/premium and cdn profiles
+	cdnAPIVersion = "2024-02-01"
+	// frontDoorAPIVersion is the Microsoft.Network api version used for classic front doors
+	frontDoorAPIVersion = "2021-06-01"
+)
+
+// cdnProfile is a Front Door Standard/Premium or CDN profile
+type cdnProfile struct {
+	armResource
+	SKU struct {
+		Name string `json:"name"`
+	} `json:"sku"`
+}
+
+// cdnHost is a Front Door endpoint or custom domain, or a CDN endpoint or custom domain
+type cdnHost struct {
+	armResource
+	Properties struct {
+		HostName              string `json:"hostName"`
+		EnabledState          string `json:"enabledState"`
+		DomainValidationState string `json:"domainValidationState"`
+		CustomHTTPSState      string `json:"customHttpsProvisioningState"`
+		OriginHostHeader      string `json:"originHostHeader"`
+	} `json:"properties"`
+}
+
+// classicFrontDoor is a Front Door (classic) resource
+type classicFrontDoor struct {
+	armResource
+	Properties struct {
+		EnabledState      string `json:"enabledState"`
+		FrontendEndpoints []struct {
+			Name       string `json:"name"`
+			Properties struct {
+				HostName string `json:"hostName"`
+			} `json:"properties"`
+		} `json:"frontendEndpoints"`
+	} `json:"properties"`
+}
+
+// frontDoorProvider is a provider for Azure Front Door and CDN endpoints and custom domains
+type frontDoorProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (fdp *frontDoorProvider) name() string {
+	return "frontdoor"
+}
+
+// GetResource returns all the Front Door and CDN hostnames for a provider.
+func (fdp *frontDoorProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	profiles, err := armList[cdnProfile](ctx, fdp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Cdn/profiles", fdp.SubscriptionID), cdnAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		var err error
+		if strings.HasSuffix(profile.SKU.Name, "_AzureFrontDoor") {
+			err = fdp.listFrontDoorHosts(ctx, &profile, list)
+		} else {
+			err = fdp.listCDNHosts(ctx, &profile, list)
+		}
+		if err != nil {
+			gologger.Warning().Msgf("error listing endpoints of profile %s: %s", profile.Name, err)
+		}
+	}
+
+	frontDoors, err := armList[classicFrontDoor](ctx, fdp.Credential, fmt.Sprintf("/subscriptions/%s/providers/Microsoft.Network/frontDoors", fdp.SubscriptionID), frontDoorAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	for _, frontDoor := range frontDoors {
+		for _, endpoint := range frontDoor.Properties.FrontendEndpoints {
+			list.Append(fdp.hostResource(endpoint.Properties.HostName, map[string]string{
+				"resource_type": "classic_frontend_endpoint",
+				"profile":       frontDoor.Name,
+				"name":          endpoint.Name,
+				"enabled_state": frontDoor.Properties.EnabledState,
+				"custom_domain": fmt.Sprint(!strings.HasSuffix(endpoint.Properties.HostName, ".azurefd.net")),
+			}))
+		}
+	}
+	return list, nil
+}
+
+// listFrontDoorHosts lists the endpoints and custom domains of a Front Door Standard/Premium profile
+func (fdp *frontDoorProvider) listFrontDoorHosts(ctx context.Context, profile *cdnProfile, list *schema.Resources) error {
+	endpoints, err := armList[cdnHost](ctx, fdp.Credential, profile.ID+"/afdEndpoints", cdnAPIVersion)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		list.Append(fdp.hostResource(endpoint.Properties.HostName, map[string]string{
+			"resource_type": "afd_endpoint",
+			"profile":       profile.Name,
+			"sku":           profile.SKU.Name,
+			"name":          endpoint.Name,
+			"enabled_state": endpoint.Properties.EnabledState,
+			"custom_domain": "false",
+		}))
+	}
+
+	domains, err := armList[cdnHost](ctx, fdp.Credential, profile.ID+"/customDomains", cdnAPIVersion)
+	if err != nil {
+		return err
+	}
+	for _, domain := range domains {
+		list.Append(fdp.hostResource(domain.Properties.HostName, map[string]string{
+			"resource_type":    "afd_custom_domain",
+			"profile":          profile.Name,
+			"sku":              profile.SKU.Name,
+			"name":             domain.Name,
+			"validation_state": domain.Properties.DomainValidationState,
+			"custom_domain":    "true",
+		}))
+	}
+	return nil
+}
+
+// listCDNHosts lists the endpoints of a CDN profile and the custom domains of each endpoint
+func (fdp *frontDoorProvider) listCDNHosts(ctx context.Context, profile *cdnProfile, list *schema.Resources) error {
+	endpoints, err := armList[cdnHost](ctx, fdp.Credential, profile.ID+"/endpoints", cdnAPIVersion)
+	if err != nil {
+		return err
+	}
+	for _, endpoint := range endpoints {
+		metadata := map[string]string{
+			"resource_type": "cdn_endpoint",
+			"profile":       profile.Name,
+			"sku":           profile.SKU.Name,
+			"name":          endpoint.Name,
+			"origin_host":   endpoint.Properties.OriginHostHeader,
+			"custom_domain": "false",
+		}
+		list.Append(fdp.hostResource(endpoint.Properties.HostName, metadata))
+
+		domains, err := armList[cdnHost](ctx, fdp.Credential, endpoint.ID+"/customDomains", cdnAPIVersion)
+		if err != nil {
+			gologger.Warning().Msgf("error listing custom domains of cdn endpoint %s: %s", endpoint.Name, err)
+			continue
+		}
+		for _, domain := range domains {
+			list.Append(fdp.hostResource(domain.Properties.HostName, map[string]string{
+				"resource_type": "cdn_custom_domain",
+				"profile":       profile.Name,
+				"sku":           profile.SKU.Name,
+				"name":          domain.Name,
+				"endpoint":      endpoint.Properties.HostName,
+				"https_state":   domain.Properties.CustomHTTPSState,
+				"custom_domain": "true",
+			}))
+		}
+	}
+	return nil
+}
+
+func (fdp *frontDoorProvider) hostResource(host string, metadata map[string]string) *schema.Resource {
+	return &schema.Resource{
+		ID:       fdp.id,
+		Public:   true,
+		DNSName:  host,
+		Provider: providerName,
+		Service:  fdp.name(),
+		Metadata: cleanMetadata(metadata),
+	}
+}
@@ -0,0 +1,85 @@
+package azure
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/keyvault/armkeyvault"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// keyVaultProvider is a provider for Azure Key Vaults
+type keyVaultProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (kvp *keyVaultProvider) name() string {
+	return "keyvault"
+}
+
+// GetResource returns the uri hostname of every key vault.
+func (kvp *keyVaultProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	client, err := armkeyvault.NewVaultsClient(kvp.SubscriptionID, kvp.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+	pager := client.NewListBySubscriptionPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, vault := range page.Value {
+			list.Merge(kvp.vaultResources(vault))
+		}
+	}
+	return list, nil
+}
+
+// graphResources returns the uri hostname of every key vault from resource graph
+func (kvp *keyVaultProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	vaults, err := graphList[*armkeyvault.Vault](ctx, graph, "microsoft.keyvault/vaults")
+	if err != nil {
+		return nil, err
+	}
+	for _, vault := range vaults {
+		list.Merge(kvp.vaultResources(vault))
+	}
+	return list, nil
+}
+
+// vaultResources returns the uri hostname of a vault
+func (kvp *keyVaultProvider) vaultResources(vault *armkeyvault.Vault) *schema.Resources {
+	list := schema.NewResources()
+	if vault.Properties == nil {
+		return list
+	}
+	vaultURI, err := url.Parse(deref(vault.Properties.VaultURI))
+	if err != nil || vaultURI.Hostname() == "" {
+		return list
+	}
+	acls := vault.Properties.NetworkACLs
+	restricted := acls != nil && acls.DefaultAction != nil && *acls.DefaultAction == armkeyvault.NetworkRuleActionDeny
+	exposure := networkExposure(deref(vault.Properties.PublicNetworkAccess), restricted)
+
+	list.Append(&schema.Resource{
+		ID:       kvp.id,
+		Public:   exposure != "disabled",
+		DNSName:  vaultURI.Hostname(),
+		Provider: providerName,
+		Service:  kvp.name(),
+		Metadata: cleanMetadata(map[string]string{
+			"name":           deref(vault.Name),
+			"location":       deref(vault.Location),
+			"network_access": exposure,
+		}),
+	})
+	return list
+}
@@ -3,8 +3,8 @@ package azure
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
 
@@ -12,7 +12,7 @@ import (
 type publicIPProvider struct {
 	id             string
 	SubscriptionID string
-	Authorizer     autorest.Authorizer
+	Credential     azcore.TokenCredential
 }
 
 func (pip *publicIPProvider) name() string {
@@ -21,18 +21,30 @@ func (pip *publicIPProvider) name() string {
 
 // GetResource returns all the resources in the store for a provider.
 func (pip *publicIPProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-
-	list := schema.NewResources()
-
 	ips, err := pip.fetchPublicIPs(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return pip.ipResources(ips), nil
+}
+
+// graphResources returns the public ips from resource graph
+func (pip *publicIPProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	ips, err := graphList[*armnetwork.PublicIPAddress](ctx, graph, "microsoft.network/publicipaddresses")
+	if err != nil {
+		return nil, err
+	}
+	return pip.ipResources(ips), nil
+}
+
+// ipResources returns the allocated addresses of the public ips
+func (pip *publicIPProvider) ipResources(ips []*armnetwork.PublicIPAddress) *schema.Resources {
+	list := schema.NewResources()
 
 	for _, ip := range ips {
 		// The IPAddress field can be nil and so we want to prevent from dereferencing
 		// a nil field in the struct
-		if ip.IPAddress == nil {
+		if ip.Properties == nil || ip.Properties.IPAddress == nil {
 			continue
 		}
 
@@ -43,36 +55,32 @@ func (pip *publicIPProvider) GetResource(ctx context.Context) (*schema.Resources
 			Service:  pip.name(),
 		}
 
-		if ip.PublicIPAddressVersion == network.IPv4 {
-			resource.PublicIPv4 = *ip.IPAddress
+		if ip.Properties.PublicIPAddressVersion == nil || *ip.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv4 {
+			resource.PublicIPv4 = *ip.Properties.IPAddress
 		} else {
-			resource.PublicIPv6 = *ip.IPAddress
+			resource.PublicIPv6 = *ip.Properties.IPAddress
 		}
 
 		list.Append(resource)
 	}
-	return list, nil
+	return list
 }
 
-func (pip *publicIPProvider) fetchPublicIPs(ctx context.Context) ([]network.PublicIPAddress, error) {
-	var ips []network.PublicIPAddress
+func (pip *publicIPProvider) fetchPublicIPs(ctx context.Context) ([]*armnetwork.PublicIPAddress, error) {
+	var ips []*armnetwork.PublicIPAddress
 
-	ipClient := network.NewPublicIPAddressesClient(pip.SubscriptionID)
-	ipClient.Authorizer = pip.Authorizer
-
-	ipsIt, err := ipClient.ListAllComplete(ctx)
+	ipClient, err := armnetwork.NewPublicIPAddressesClient(pip.SubscriptionID, pip.Credential, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	for ipsIt.NotDone() {
-		ip := ipsIt.Value()
-		ips = append(ips, ip)
-
-		if err = ipsIt.NextWithContext(ctx); err != nil {
+	pager := ipClient.NewListAllPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
 			return ips, err
 		}
+		ips = append(ips, page.Value...)
 	}
-
-	return ips, err
+	return ips, nil
 }
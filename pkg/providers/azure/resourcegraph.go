@@ -0,0 +1,119 @@
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resourcegraph/armresourcegraph"
+	"github.com/pkg/errors"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// resourceGraphSubscriptionBatch is the maximum number of subscriptions
+// resource graph accepts in a single query
+const resourceGraphSubscriptionBatch = 1000
+
+// resourceGraph runs queries against azure resource graph across all the
+// subscriptions of the provider at once
+type resourceGraph struct {
+	client        *armresourcegraph.Client
+	credential    azcore.TokenCredential
+	subscriptions []string
+}
+
+// resourceGraphService is implemented by the services whose resources can
+// be listed from resource graph instead of their own resource provider api
+type resourceGraphService interface {
+	serviceProvider
+	graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error)
+}
+
+func newResourceGraph(credential azcore.TokenCredential, subscriptions []string) (*resourceGraph, error) {
+	client, err := armresourcegraph.NewClient(credential, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &resourceGraph{client: client, credential: credential, subscriptions: subscriptions}, nil
+}
+
+// graphList returns the resources of the given types, each row being decoded
+// into T the same way as the resource manager api response of the resource
+func graphList[T any](ctx context.Context, graph *resourceGraph, resourceTypes ...string) ([]T, error) {
+	quoted := make([]string, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		quoted = append(quoted, fmt.Sprintf("'%s'", resourceType))
+	}
+	return graphQuery[T](ctx, graph, fmt.Sprintf("Resources | where type in~ (%s)", strings.Join(quoted, ", ")))
+}
+
+// graphQuery pages through the results of a query for every batch of
+// subscriptions, the rows being decoded into T
+func graphQuery[T any](ctx context.Context, graph *resourceGraph, query string) ([]T, error) {
+	var items []T
+	for start := 0; start < len(graph.subscriptions); start += resourceGraphSubscriptionBatch {
+		end := min(start+resourceGraphSubscriptionBatch, len(graph.subscriptions))
+
+		request := armresourcegraph.QueryRequest{
+			Query:         to.Ptr(query),
+			Subscriptions: to.SliceOfPtrs(graph.subscriptions[start:end]...),
+			Options: &armresourcegraph.QueryRequestOptions{
+				ResultFormat: to.Ptr(armresourcegraph.ResultFormatObjectArray),
+			},
+		}
+		for {
+			resp, err := graph.client.Resources(ctx, request, nil)
+			if err != nil {
+				return nil, errors.Wrap(err, "could not query resource graph")
+			}
+			// the rows are re-encoded so that the sdk models decode them
+			data, err := json.Marshal(resp.Data)
+			if err != nil {
+				return nil, err
+			}
+			var rows []T
+			if err := json.Unmarshal(data, &rows); err != nil {
+				return nil, errors.Wrap(err, "could not decode resource graph rows")
+			}
+			items = append(items, rows...)
+
+			if resp.SkipToken == nil || *resp.SkipToken == "" {
+				break
+			}
+			request.Options.SkipToken = resp.SkipToken
+		}
+	}
+	return items, nil
+}
+
+// resourceGraphResources lists the enabled services that support resource
+// graph for every subscription at once. The services that have to be listed
+// through their own api are returned so that the caller can enumerate them.
+func (p *Provider) resourceGraphResources(ctx context.Context) (*schema.Resources, schema.ServiceMap, error) {
+	graph, err := newResourceGraph(p.Credential, p.SubscriptionIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resources := schema.NewResources()
+	remaining := make(schema.ServiceMap)
+	for _, service := range p.serviceProviders("") {
+		if !p.services.Has(service.name()) {
+			continue
+		}
+		graphService, ok := service.(resourceGraphService)
+		if !ok {
+			remaining[service.name()] = struct{}{}
+			continue
+		}
+		serviceResources, err := graphService.graphResources(ctx, graph)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "could not list %s resources", service.name())
+		}
+		resources.Merge(serviceResources)
+	}
+	return resources, remaining, nil
+}
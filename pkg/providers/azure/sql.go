@@ -0,0 +1,108 @@
+package azure
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// databaseServer is an Azure SQL, PostgreSQL flexible or MySQL flexible server
+type databaseServer struct {
+	armResource
+	Properties struct {
+		FullyQualifiedDomainName string `json:"fullyQualifiedDomainName"`
+		State                    string `json:"state"`
+		Version                  string `json:"version"`
+		// PublicNetworkAccess is set on sql servers, the flexible servers use Network
+		PublicNetworkAccess string `json:"publicNetworkAccess"`
+		Network             *struct {
+			PublicNetworkAccess string `json:"publicNetworkAccess"`
+		} `json:"network"`
+	} `json:"properties"`
+}
+
+// firewallRule is a server level firewall rule of a database server
+type firewallRule struct {
+	armResource
+	Properties struct {
+		StartIPAddress string `json:"startIpAddress"`
+		EndIPAddress   string `json:"endIpAddress"`
+	} `json:"properties"`
+}
+
+// databaseServerTypes are the resource types listed by the sql service
+var databaseServerTypes = []struct {
+	engine       string
+	resourceType string
+	apiVersion   string
+}{
+	{engine: "sqlserver", resourceType: "Microsoft.Sql/servers", apiVersion: "2021-11-01"},
+	{engine: "postgresql", resourceType: "Microsoft.DBforPostgreSQL/flexibleServers", apiVersion: "2022-12-01"},
+	{engine: "mysql", resourceType: "Microsoft.DBforMySQL/flexibleServers", apiVersion: "2023-06-30"},
+}
+
+// sqlProvider is a provider for Azure SQL, PostgreSQL and MySQL servers
+type sqlProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (sp *sqlProvider) name() string {
+	return "sql"
+}
+
+// GetResource returns the hostname of every database server.
+func (sp *sqlProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, serverType := range databaseServerTypes {
+		servers, err := armList[databaseServer](ctx, sp.Credential, fmt.Sprintf("/subscriptions/%s/providers/%s", sp.SubscriptionID, serverType.resourceType), serverType.apiVersion)
+		if err != nil {
+			gologger.Warning().Msgf("error listing %s servers: %s", serverType.engine, err)
+			continue
+		}
+		for _, server := range servers {
+			publicNetworkAccess := server.Properties.PublicNetworkAccess
+			if server.Properties.Network != nil {
+				publicNetworkAccess = server.Properties.Network.PublicNetworkAccess
+			}
+
+			// servers deny all traffic unless a firewall rule allows the whole internet
+			restricted := true
+			rules, err := armList[firewallRule](ctx, sp.Credential, server.ID+"/firewallRules", serverType.apiVersion)
+			if err != nil {
+				gologger.Warning().Msgf("error listing firewall rules of %s: %s", server.Name, err)
+			}
+			for _, rule := range rules {
+				if rule.Properties.StartIPAddress == "0.0.0.0" && rule.Properties.EndIPAddress == "255.255.255.255" {
+					restricted = false
+				}
+			}
+			exposure := networkExposure(publicNetworkAccess, restricted)
+
+			list.Append(&schema.Resource{
+				ID:       sp.id,
+				Public:   exposure != "disabled",
+				DNSName:  strings.ToLower(server.Properties.FullyQualifiedDomainName),
+				Provider: providerName,
+				Service:  sp.name(),
+				Metadata: cleanMetadata(map[string]string{
+					"name":           server.Name,
+					"location":       server.Location,
+					"engine":         serverType.engine,
+					"version":        server.Properties.Version,
+					"state":          server.Properties.State,
+					"network_access": exposure,
+					"firewall_rules": strconv.Itoa(len(rules)),
+				}),
+			})
+		}
+	}
+	return list, nil
+}
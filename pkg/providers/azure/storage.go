@@ -0,0 +1,121 @@
+package azure
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+// storageProvider is a provider for Azure Storage accounts
+type storageProvider struct {
+	id             string
+	SubscriptionID string
+	Credential     azcore.TokenCredential
+}
+
+func (sp *storageProvider) name() string {
+	return "storage"
+}
+
+// GetResource returns the service endpoints of every storage account.
+func (sp *storageProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	client, err := armstorage.NewAccountsClient(sp.SubscriptionID, sp.Credential, nil)
+	if err != nil {
+		return nil, err
+	}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range page.Value {
+			if account.Properties != nil {
+				list.Merge(sp.accountResources(account))
+			}
+		}
+	}
+	return list, nil
+}
+
+// graphResources returns the service endpoints of every storage account from resource graph
+func (sp *storageProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	accounts, err := graphList[*armstorage.Account](ctx, graph, "microsoft.storage/storageaccounts")
+	if err != nil {
+		return nil, err
+	}
+	for _, account := range accounts {
+		if account.Properties != nil {
+			list.Merge(sp.accountResources(account))
+		}
+	}
+	return list, nil
+}
+
+// accountResources returns the blob, dfs, file, queue, table and static website
+// endpoints of an account along with its custom domain
+func (sp *storageProvider) accountResources(account *armstorage.Account) *schema.Resources {
+	list := schema.NewResources()
+	properties := account.Properties
+
+	var publicNetworkAccess string
+	if properties.PublicNetworkAccess != nil {
+		publicNetworkAccess = string(*properties.PublicNetworkAccess)
+	}
+	restricted := properties.NetworkRuleSet != nil && properties.NetworkRuleSet.DefaultAction != nil && *properties.NetworkRuleSet.DefaultAction == armstorage.DefaultActionDeny
+	exposure := networkExposure(publicNetworkAccess, restricted)
+	allowBlobPublicAccess := properties.AllowBlobPublicAccess != nil && *properties.AllowBlobPublicAccess
+
+	appendHost := func(host, endpointType string) {
+		if host == "" {
+			return
+		}
+		list.Append(&schema.Resource{
+			ID:       sp.id,
+			Public:   exposure != "disabled",
+			DNSName:  host,
+			Provider: providerName,
+			Service:  sp.name(),
+			Metadata: cleanMetadata(map[string]string{
+				"name":                     deref(account.Name),
+				"location":                 deref(account.Location),
+				"endpoint_type":            endpointType,
+				"network_access":           exposure,
+				"allow_blob_public_access": strconv.FormatBool(allowBlobPublicAccess),
+			}),
+		})
+	}
+
+	for _, endpoints := range []*armstorage.Endpoints{properties.PrimaryEndpoints, properties.SecondaryEndpoints} {
+		if endpoints == nil {
+			continue
+		}
+		for _, endpoint := range []struct {
+			endpointType string
+			url          *string
+		}{
+			{"blob", endpoints.Blob},
+			{"dfs", endpoints.Dfs},
+			{"file", endpoints.File},
+			{"queue", endpoints.Queue},
+			{"table", endpoints.Table},
+			{"web", endpoints.Web},
+		} {
+			if parsed, err := url.Parse(deref(endpoint.url)); err == nil {
+				appendHost(parsed.Hostname(), endpoint.endpointType)
+			}
+		}
+	}
+	if properties.CustomDomain != nil {
+		appendHost(deref(properties.CustomDomain.Name), "custom_domain")
+	}
+	return list
+}
@@ -3,8 +3,8 @@ package azure
 import (
 	"context"
 
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/trafficmanager/mgmt/trafficmanager"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/trafficmanager/armtrafficmanager"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 )
 
@@ -12,7 +12,7 @@ import (
 type trafficManagerProvider struct {
 	id             string
 	SubscriptionID string
-	Authorizer     autorest.Authorizer
+	Credential     azcore.TokenCredential
 }
 
 // name returns the name of the provider
@@ -22,36 +22,55 @@ func (tmp *trafficManagerProvider) name() string {
 
 // GetResource returns all the Traffic Manager hostnames for a provider.
 func (tmp *trafficManagerProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-
 	profiles, err := tmp.fetchTrafficManagerProfiles(ctx)
 	if err != nil {
 		return nil, err
 	}
+	return tmp.profileResources(profiles), nil
+}
 
-	for _, profile := range *profiles {
-		if profile.ProfileProperties != nil && profile.ProfileProperties.DNSConfig != nil && profile.ProfileProperties.DNSConfig.Fqdn != nil {
+// graphResources returns the Traffic Manager hostnames from resource graph
+func (tmp *trafficManagerProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	profiles, err := graphList[*armtrafficmanager.Profile](ctx, graph, "microsoft.network/trafficmanagerprofiles")
+	if err != nil {
+		return nil, err
+	}
+	return tmp.profileResources(profiles), nil
+}
+
+// profileResources returns the hostname of every profile
+func (tmp *trafficManagerProvider) profileResources(profiles []*armtrafficmanager.Profile) *schema.Resources {
+	list := schema.NewResources()
+
+	for _, profile := range profiles {
+		if profile.Properties != nil && profile.Properties.DNSConfig != nil && profile.Properties.DNSConfig.Fqdn != nil {
 			resource := &schema.Resource{
 				Provider: providerName,
 				ID:       tmp.id,
-				DNSName:  *profile.ProfileProperties.DNSConfig.Fqdn,
+				DNSName:  *profile.Properties.DNSConfig.Fqdn,
 				Service:  tmp.name(),
 			}
 			list.Append(resource)
 		}
 	}
-	return list, nil
+	return list
 }
 
 // fetchTrafficManagerProfiles retrieves all Traffic Manager profiles for the subscription.
-func (tmp *trafficManagerProvider) fetchTrafficManagerProfiles(ctx context.Context) (*[]trafficmanager.Profile, error) {
-	client := trafficmanager.NewProfilesClient(tmp.SubscriptionID)
-	client.Authorizer = tmp.Authorizer
-
-	profilesIt, err := client.ListBySubscription(ctx)
+func (tmp *trafficManagerProvider) fetchTrafficManagerProfiles(ctx context.Context) ([]*armtrafficmanager.Profile, error) {
+	client, err := armtrafficmanager.NewProfilesClient(tmp.SubscriptionID, tmp.Credential, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	return profilesIt.Value, nil
+	var profiles []*armtrafficmanager.Profile
+	pager := client.NewListBySubscriptionPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, page.Value...)
+	}
+	return profiles, nil
 }
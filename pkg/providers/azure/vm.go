@@ -2,24 +2,20 @@ package azure
 
 import (
 	"context"
-	"sync"
+	"strings"
 
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/compute/mgmt/compute"
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/network/mgmt/network"
-	"github.com/Azure/azure-sdk-for-go/profiles/latest/resources/mgmt/resources"
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure"
-	"github.com/alitto/pond/v2"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/network/armnetwork/v6"
 	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
-	"github.com/projectdiscovery/gologger"
 )
 
 // vmProvider is an instance provider for Azure API
 type vmProvider struct {
 	id             string
 	SubscriptionID string
-	Authorizer     autorest.Authorizer
+	Credential     azcore.TokenCredential
 }
 
 func (d *vmProvider) name() string {
@@ -27,169 +23,149 @@ func (d *vmProvider) name() string {
 }
 
 // GetResource returns all the resources in the store for a provider.
+//
+// The virtual machines, network interfaces and public ips of the subscription
+// are each listed once and joined in memory, instead of resolving every
+// interface and address of every virtual machine with its own call.
 func (d *vmProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
-	list := schema.NewResources()
-	mu := &sync.Mutex{}
-
-	groups, err := fetchResouceGroups(ctx, d.SubscriptionID, d.Authorizer)
+	vms, err := fetchVMList(ctx, d)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create a goroutine pool with size that matches Azure API limitations
-	// Adjust pool size based on your Azure throttling limits
-	pool := pond.NewPool(10)
-
-	for _, group := range groups {
-		group := group // Create local copy for goroutine
-
-		// Submit task to the pool
-		pool.Submit(func() {
-			resourcesSlice, err := d.processResourceGroup(ctx, group)
-			if err != nil {
-				gologger.Warning().Msgf("error processing resource group %s: %s", group, err)
-			}
-
-			mu.Lock()
-			for _, resource := range resourcesSlice {
-				list.Append(resource)
-			}
-			mu.Unlock()
-		})
+	if len(vms) == 0 {
+		return schema.NewResources(), nil
 	}
-	pool.StopAndWait()
-
-	return list, nil
+	nics, err := fetchInterfaces(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	publicIPs, err := (&publicIPProvider{SubscriptionID: d.SubscriptionID, Credential: d.Credential}).fetchPublicIPs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "error traversing public ip list")
+	}
+	return d.joinResources(vms, nics, publicIPs), nil
 }
 
-func (d *vmProvider) processResourceGroup(ctx context.Context, group string) ([]*schema.Resource, error) {
-	vmList, err := fetchVMList(ctx, group, d)
+// graphResources returns the virtual machine addresses from resource graph
+func (d *vmProvider) graphResources(ctx context.Context, graph *resourceGraph) (*schema.Resources, error) {
+	vms, err := graphList[*armcompute.VirtualMachine](ctx, graph, "microsoft.compute/virtualmachines")
+	if err != nil {
+		return nil, err
+	}
+	if len(vms) == 0 {
+		return schema.NewResources(), nil
+	}
+	nics, err := graphList[*armnetwork.Interface](ctx, graph, "microsoft.network/networkinterfaces")
+	if err != nil {
+		return nil, err
+	}
+	publicIPs, err := graphList[*armnetwork.PublicIPAddress](ctx, graph, "microsoft.network/publicipaddresses")
 	if err != nil {
-		return nil, errors.Wrap(err, "error fetching vm list")
+		return nil, err
 	}
+	return d.joinResources(vms, nics, publicIPs), nil
+}
 
-	var resources []*schema.Resource
-	for _, vm := range vmList {
-		nics := *vm.NetworkProfile.NetworkInterfaces
+// joinResources returns the private and public addresses of the virtual
+// machines by following their network interfaces to the public ips
+func (d *vmProvider) joinResources(vms []*armcompute.VirtualMachine, nics []*armnetwork.Interface, publicIPs []*armnetwork.PublicIPAddress) *schema.Resources {
+	list := schema.NewResources()
+
+	nicsByID := make(map[string]*armnetwork.Interface, len(nics))
+	for _, nic := range nics {
+		if nic.ID != nil {
+			nicsByID[strings.ToLower(*nic.ID)] = nic
+		}
+	}
+	publicIPsByID := make(map[string]*armnetwork.PublicIPAddress, len(publicIPs))
+	for _, ip := range publicIPs {
+		if ip.ID != nil {
+			publicIPsByID[strings.ToLower(*ip.ID)] = ip
+		}
+	}
 
-		for _, nic := range nics {
-			res, err := azure.ParseResourceID(*nic.ID)
-			if err != nil {
-				gologger.Warning().Msgf("error parsing resource ID: %s", err)
+	for _, vm := range vms {
+		if vm.Properties == nil || vm.Properties.NetworkProfile == nil {
+			continue
+		}
+		for _, nicRef := range vm.Properties.NetworkProfile.NetworkInterfaces {
+			if nicRef.ID == nil {
 				continue
 			}
-
-			ipconfigList, err := fetchIPConfigList(ctx, group, res.ResourceName, d)
-			if err != nil {
-				gologger.Warning().Msgf("error fetching IP configs for NIC %s: %s", res.ResourceName, err)
+			nic, ok := nicsByID[strings.ToLower(*nicRef.ID)]
+			if !ok || nic.Properties == nil {
 				continue
 			}
-
-			for _, ipConfig := range ipconfigList {
-				if ipConfig.PublicIPAddress == nil {
-					continue
-				}
-
-				res, err := azure.ParseResourceID(*ipConfig.PublicIPAddress.ID)
-				if err != nil {
-					gologger.Warning().Msgf("error parsing resource ID: %s", err)
+			for _, ipConfig := range nic.Properties.IPConfigurations {
+				if ipConfig.Properties == nil || ipConfig.Properties.PublicIPAddress == nil || ipConfig.Properties.PublicIPAddress.ID == nil {
 					continue
 				}
-
-				publicIP, err := fetchPublicIP(ctx, group, res.ResourceName, d)
-				if err != nil {
-					gologger.Warning().Msgf("error fetching public IP %s: %s", res.ResourceName, err)
-					continue
-				}
-
-				if publicIP.IPAddress == nil {
+				publicIP, ok := publicIPsByID[strings.ToLower(*ipConfig.Properties.PublicIPAddress.ID)]
+				if !ok || publicIP.Properties == nil || publicIP.Properties.IPAddress == nil {
 					continue
 				}
 
 				resource := &schema.Resource{
-					Provider:    providerName,
-					ID:          d.id,
-					PrivateIpv4: *ipConfig.PrivateIPAddress,
-					Service:     d.name(),
+					Provider: providerName,
+					ID:       d.id,
+					Service:  d.name(),
 				}
-
-				if publicIP.PublicIPAddressVersion == network.IPv4 {
-					resource.PublicIPv4 = *publicIP.IPAddress
+				if ipConfig.Properties.PrivateIPAddress != nil {
+					resource.PrivateIpv4 = *ipConfig.Properties.PrivateIPAddress
+				}
+				if publicIP.Properties.PublicIPAddressVersion == nil || *publicIP.Properties.PublicIPAddressVersion == armnetwork.IPVersionIPv4 {
+					resource.PublicIPv4 = *publicIP.Properties.IPAddress
 				} else {
-					resource.PublicIPv6 = *publicIP.IPAddress
+					resource.PublicIPv6 = *publicIP.Properties.IPAddress
 				}
+				list.Append(resource)
 
-				resources = append(resources, resource)
-
-				if publicIP.DNSSettings.Fqdn != nil {
-					resources = append(resources, &schema.Resource{
+				if publicIP.Properties.DNSSettings != nil && publicIP.Properties.DNSSettings.Fqdn != nil {
+					list.Append(&schema.Resource{
 						Provider: providerName,
 						ID:       d.id,
-						DNSName:  *publicIP.DNSSettings.Fqdn,
+						DNSName:  *publicIP.Properties.DNSSettings.Fqdn,
 						Service:  d.name(),
 					})
 				}
 			}
 		}
 	}
-	return resources, nil
+	return list
 }
 
-func fetchResouceGroups(ctx context.Context, subscriptionID string, authorizer autorest.Authorizer) (resGrpList []string, err error) {
-	grClient := resources.NewGroupsClient(subscriptionID)
-	grClient.Authorizer = authorizer
-
-	for list, err := grClient.ListComplete(ctx, "", nil); list.NotDone(); err = list.Next() {
-
-		if err != nil {
-			return nil, errors.Wrap(err, "error traversing resource group list")
-		}
-		resGrp := *list.Value().Name
-		resGrpList = append(resGrpList, resGrp)
+func fetchVMList(ctx context.Context, sess *vmProvider) (VMList []*armcompute.VirtualMachine, err error) {
+	vmClient, err := armcompute.NewVirtualMachinesClient(sess.SubscriptionID, sess.Credential, nil)
+	if err != nil {
+		return nil, err
 	}
-	return resGrpList, err
-}
-
-func fetchVMList(ctx context.Context, group string, sess *vmProvider) (VMList []compute.VirtualMachine, err error) {
-	vmClient := compute.NewVirtualMachinesClient(sess.SubscriptionID)
-	vmClient.Authorizer = sess.Authorizer
 
-	for vm, err := vmClient.ListComplete(context.Background(), group, ""); vm.NotDone(); err = vm.Next() {
+	pager := vmClient.NewListAllPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
 		if err != nil {
 			return nil, errors.Wrap(err, "error traverising vm list")
 		}
-		VMList = append(VMList, vm.Value())
+		VMList = append(VMList, page.Value...)
 	}
-	return VMList, err
+	return VMList, nil
 }
 
-func fetchIPConfigList(ctx context.Context, group, nic string, sess *vmProvider) (IPConfigList []network.InterfaceIPConfigurationPropertiesFormat, err error) {
-
-	nicClient := network.NewInterfacesClient(sess.SubscriptionID)
-	nicClient.Authorizer = sess.Authorizer
-
-	nicRes, err := nicClient.Get(ctx, group, nic, "")
+// fetchInterfaces returns the network interfaces of the subscription
+func fetchInterfaces(ctx context.Context, sess *vmProvider) ([]*armnetwork.Interface, error) {
+	nicClient, err := armnetwork.NewInterfacesClient(sess.SubscriptionID, sess.Credential, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	ipconfigs := *nicRes.IPConfigurations
-	for _, v := range ipconfigs {
-		IPConfigList = append(IPConfigList, *v.InterfaceIPConfigurationPropertiesFormat)
-	}
-
-	return IPConfigList, err
-}
-
-func fetchPublicIP(ctx context.Context, group, publicIP string, sess *vmProvider) (IP network.PublicIPAddress, err error) {
-
-	ipClient := network.NewPublicIPAddressesClient(sess.SubscriptionID)
-	ipClient.Authorizer = sess.Authorizer
-
-	IP, err = ipClient.Get(ctx, group, publicIP, "")
-	if err != nil {
-		return network.PublicIPAddress{}, err
+	var nics []*armnetwork.Interface
+	pager := nicClient.NewListAllPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "error traversing network interface list")
+		}
+		nics = append(nics, page.Value...)
 	}
-
-	return IP, err
+	return nics, nil
 }
@@ -0,0 +1,86 @@
+package gcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"google.golang.org/api/compute/v1"
+)
+
+// addressProvider is a provider for gcp reserved static addresses
+type addressProvider struct {
+	id       string
+	compute  *compute.Service
+	projects []string
+	report   *projectErrorReport
+}
+
+func (d *addressProvider) name() string {
+	return "address"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (d *addressProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, project := range d.projects {
+		err := d.compute.Addresses.AggregatedList(project).Pages(ctx, func(addresses *compute.AddressAggregatedList) error {
+			for scope, scopedList := range addresses.Items {
+				// global addresses are listed below
+				if scope == "global" {
+					continue
+				}
+				for _, address := range scopedList.Addresses {
+					list.Append(d.addressResource(project, address))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+			continue
+		}
+
+		err = d.compute.GlobalAddresses.List(project).Pages(ctx, func(addresses *compute.AddressList) error {
+			for _, address := range addresses.Items {
+				list.Append(d.addressResource(project, address))
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+	}
+	return list, nil
+}
+
+// addressResource returns a reserved address, unattached ones included
+func (d *addressProvider) addressResource(project string, address *compute.Address) *schema.Resource {
+	users := make([]string, 0, len(address.Users))
+	for _, user := range address.Users {
+		users = append(users, lastSegment(user))
+	}
+	resource := &schema.Resource{
+		ID:       d.id,
+		Public:   address.AddressType != "INTERNAL",
+		Provider: providerName,
+		Service:  d.name(),
+		Metadata: cleanMetadata(map[string]string{
+			"project":      project,
+			"region":       regionOf(address.Region),
+			"name":         address.Name,
+			"status":       address.Status,
+			"address_type": address.AddressType,
+			"purpose":      address.Purpose,
+			"network_tier": address.NetworkTier,
+			"users":        strings.Join(users, ","),
+		}),
+	}
+	if address.IpVersion == "IPV6" || strings.Contains(address.Address, ":") {
+		resource.PublicIPv6 = address.Address
+	} else {
+		resource.PublicIPv4 = address.Address
+	}
+	return resource
+}
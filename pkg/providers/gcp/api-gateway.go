@@ -0,0 +1,76 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	apigateway "google.golang.org/api/apigateway/v1"
+	"google.golang.org/api/servicemanagement/v1"
+)
+
+// apiGatewayProvider is a provider for gcp API Gateway gateways and Cloud Endpoints services
+type apiGatewayProvider struct {
+	id                string
+	apigateway        *apigateway.Service
+	servicemanagement *servicemanagement.APIService
+	projects          []string
+	report            *projectErrorReport
+}
+
+func (d *apiGatewayProvider) name() string {
+	return "api-gateway"
+}
+
+// GetResource returns all the API Gateway resources in the store for a provider.
+func (d *apiGatewayProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, project := range d.projects {
+		err := d.apigateway.Projects.Locations.Gateways.List(fmt.Sprintf("projects/%s/locations/-", project)).Pages(ctx, func(gateways *apigateway.ApigatewayListGatewaysResponse) error {
+			for _, gateway := range gateways.Gateways {
+				list.Append(d.hostResource(gateway.DefaultHostname, map[string]string{
+					"project":       project,
+					"resource_type": "gateway",
+					"name":          lastSegment(gateway.Name),
+					"api_config":    gateway.ApiConfig,
+					"state":         gateway.State,
+				}))
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+
+		// cloud endpoints services are named after the hostname they are served on
+		err = d.servicemanagement.Services.List().ProducerProjectId(project).Pages(ctx, func(services *servicemanagement.ListServicesResponse) error {
+			for _, service := range services.Services {
+				if !strings.Contains(service.ServiceName, ".") {
+					continue
+				}
+				list.Append(d.hostResource(service.ServiceName, map[string]string{
+					"project":       project,
+					"resource_type": "endpoints",
+				}))
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+	}
+	return list, nil
+}
+
+func (d *apiGatewayProvider) hostResource(host string, metadata map[string]string) *schema.Resource {
+	return &schema.Resource{
+		ID:       d.id,
+		Public:   true,
+		DNSName:  host,
+		Provider: providerName,
+		Service:  d.name(),
+		Metadata: cleanMetadata(metadata),
+	}
+}
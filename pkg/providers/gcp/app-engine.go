@@ -0,0 +1,103 @@
+package gcp
+
+import (
+	"context"
+	"maps"
+	"net/url"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"google.golang.org/api/appengine/v1"
+)
+
+// appEngineProvider is a provider for gcp App Engine services, versions and domain mappings
+type appEngineProvider struct {
+	id        string
+	appengine *appengine.APIService
+	projects  []string
+	report    *projectErrorReport
+}
+
+func (d *appEngineProvider) name() string {
+	return "app-engine"
+}
+
+// GetResource returns all the App Engine resources in the store for a provider.
+func (d *appEngineProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, project := range d.projects {
+		app, err := d.appengine.Apps.Get(project).Context(ctx).Do()
+		// most projects have no application, which is not an error
+		if isNotFound(err) {
+			continue
+		}
+		if err != nil {
+			d.report.record(project, d.name(), err)
+			continue
+		}
+		metadata := map[string]string{
+			"project":        project,
+			"location":       app.LocationId,
+			"serving_status": app.ServingStatus,
+		}
+		list.Append(d.hostResource(app.DefaultHostname, "default", metadata))
+
+		err = d.appengine.Apps.Services.List(app.Id).Pages(ctx, func(services *appengine.ListServicesResponse) error {
+			for _, service := range services.Services {
+				serviceMetadata := maps.Clone(metadata)
+				serviceMetadata["service"] = service.Id
+				list.Append(d.hostResource(service.Id+"-dot-"+app.DefaultHostname, "service", serviceMetadata))
+
+				err := d.appengine.Apps.Services.Versions.List(app.Id, service.Id).Pages(ctx, func(versions *appengine.ListVersionsResponse) error {
+					for _, version := range versions.Versions {
+						versionURL, err := url.Parse(version.VersionUrl)
+						if err != nil {
+							continue
+						}
+						versionMetadata := maps.Clone(serviceMetadata)
+						versionMetadata["version"] = version.Id
+						versionMetadata["serving_status"] = version.ServingStatus
+						list.Append(d.hostResource(versionURL.Hostname(), "version", versionMetadata))
+					}
+					return nil
+				})
+				if err != nil {
+					d.report.record(project, d.name(), err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+
+		err = d.appengine.Apps.DomainMappings.List(app.Id).Pages(ctx, func(mappings *appengine.ListDomainMappingsResponse) error {
+			for _, mapping := range mappings.DomainMappings {
+				list.Append(d.hostResource(mapping.Id, "custom_domain", map[string]string{
+					"project":        project,
+					"location":       app.LocationId,
+					"custom_domain":  "true",
+					"default_domain": app.DefaultHostname,
+				}))
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+	}
+	return list, nil
+}
+
+func (d *appEngineProvider) hostResource(host, resourceType string, metadata map[string]string) *schema.Resource {
+	resourceMetadata := maps.Clone(metadata)
+	resourceMetadata["resource_type"] = resourceType
+	return &schema.Resource{
+		ID:       d.id,
+		Public:   true,
+		DNSName:  host,
+		Provider: providerName,
+		Service:  d.name(),
+		Metadata: cleanMetadata(resourceMetadata),
+	}
+}
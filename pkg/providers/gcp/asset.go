@@ -0,0 +1,227 @@
+package gcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	cloudasset "google.golang.org/api/cloudasset/v1"
+	"google.golang.org/api/cloudfunctions/v1"
+	"google.golang.org/api/compute/v1"
+	run "google.golang.org/api/run/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+	"google.golang.org/api/storage/v1"
+)
+
+const cloudAssetScope = "cloud_asset_scope"
+
+// assetTypes are the cloud asset types searched for every service that can
+// be listed from cloud asset inventory
+var assetTypes = map[string][]string{
+	"compute":        {"compute.googleapis.com/Instance"},
+	"address":        {"compute.googleapis.com/Address", "compute.googleapis.com/GlobalAddress"},
+	"load-balancer":  {"compute.googleapis.com/ForwardingRule", "compute.googleapis.com/GlobalForwardingRule", "compute.googleapis.com/UrlMap"},
+	"s3":             {"storage.googleapis.com/Bucket"},
+	"cloud-function": {"cloudfunctions.googleapis.com/CloudFunction"},
+	"cloud-run":      {"run.googleapis.com/Service"},
+	"cloud-sql":      {"sqladmin.googleapis.com/Instance"},
+}
+
+// invokerRoles are the roles that make a resource public when granted to
+// allUsers or allAuthenticatedUsers, as checked by the direct services
+var invokerRoles = map[string]string{
+	"storage.googleapis.com/Bucket":               "roles/storage.objectViewer",
+	"cloudfunctions.googleapis.com/CloudFunction": "roles/cloudfunctions.invoker",
+	"run.googleapis.com/Service":                  "roles/run.invoker",
+}
+
+// cloudAssetProvider lists the resources of every project under a scope
+// with cloud asset inventory searches instead of the apis of each service
+type cloudAssetProvider struct {
+	id       string
+	asset    *cloudasset.Service
+	scope    string
+	services schema.ServiceMap
+	// projects are the numbers of the projects to keep the assets of, all
+	// the assets of the scope being kept if nil
+	projects map[string]struct{}
+}
+
+// GetResource returns the resources of the services from the full data of
+// the assets, mapped the same way as the resources of the direct services.
+func (d *cloudAssetProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	var types []string
+	for service := range d.services {
+		types = append(types, assetTypes[service]...)
+	}
+	if len(types) == 0 {
+		return list, nil
+	}
+	public, err := d.publicResources(ctx, types)
+	if err != nil {
+		return nil, err
+	}
+
+	search := d.asset.V1.SearchAllResources(d.scope).AssetTypes(types...).ReadMask("name,assetType,project,versionedResources")
+	err = search.Pages(ctx, func(resp *cloudasset.SearchAllResourcesResponse) error {
+		for _, result := range resp.Results {
+			if !d.inProjects(result) {
+				continue
+			}
+			resources, err := d.assetResources(result, public[result.Name])
+			if err != nil {
+				return errorutil.NewWithErr(err).Msgf("could not decode asset %s", result.Name)
+			}
+			list.Merge(resources)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not search cloud assets of %s", d.scope)
+	}
+	return list, nil
+}
+
+// inProjects returns true if the asset belongs to one of the projects
+func (d *cloudAssetProvider) inProjects(result *cloudasset.ResourceSearchResult) bool {
+	if d.projects == nil {
+		return true
+	}
+	_, ok := d.projects[strings.TrimPrefix(result.Project, "projects/")]
+	return ok
+}
+
+// publicResources returns the names of the assets whose iam policy grants
+// their invoker role to allUsers or allAuthenticatedUsers
+func (d *cloudAssetProvider) publicResources(ctx context.Context, types []string) (map[string]bool, error) {
+	public := make(map[string]bool)
+
+	var policyTypes []string
+	for _, assetType := range types {
+		if _, ok := invokerRoles[assetType]; ok {
+			policyTypes = append(policyTypes, assetType)
+		}
+	}
+	if len(policyTypes) == 0 {
+		return public, nil
+	}
+
+	search := d.asset.V1.SearchAllIamPolicies(d.scope).AssetTypes(policyTypes...).Query("policy:(allUsers OR allAuthenticatedUsers)")
+	err := search.Pages(ctx, func(resp *cloudasset.SearchAllIamPoliciesResponse) error {
+		for _, result := range resp.Results {
+			if result.Policy == nil {
+				continue
+			}
+			for _, binding := range result.Policy.Bindings {
+				if binding.Role != invokerRoles[result.AssetType] {
+					continue
+				}
+				for _, member := range binding.Members {
+					if member == "allUsers" || member == "allAuthenticatedUsers" {
+						public[result.Resource] = true
+					}
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not search iam policies of %s", d.scope)
+	}
+	return public, nil
+}
+
+// assetResources decodes the resource data of an asset into the model of
+// its service api and returns its resources
+func (d *cloudAssetProvider) assetResources(result *cloudasset.ResourceSearchResult, public bool) (*schema.Resources, error) {
+	list := schema.NewResources()
+	project := assetProject(result)
+
+	switch result.AssetType {
+	case "compute.googleapis.com/Instance":
+		instance := &compute.Instance{}
+		if ok, err := decodeAsset(result, instance); !ok {
+			return list, err
+		}
+		vmProvider := &cloudVMProvider{id: d.id}
+		for _, nic := range instance.NetworkInterfaces {
+			list.Merge(vmProvider.networkInterfaceResources(project, instance, nic))
+		}
+	case "compute.googleapis.com/Address", "compute.googleapis.com/GlobalAddress":
+		address := &compute.Address{}
+		if ok, err := decodeAsset(result, address); !ok {
+			return list, err
+		}
+		list.Append((&addressProvider{id: d.id}).addressResource(project, address))
+	case "compute.googleapis.com/ForwardingRule", "compute.googleapis.com/GlobalForwardingRule":
+		rule := &compute.ForwardingRule{}
+		if ok, err := decodeAsset(result, rule); !ok {
+			return list, err
+		}
+		list.Append((&loadBalancerProvider{id: d.id}).forwardingRuleResource(project, rule))
+	case "compute.googleapis.com/UrlMap":
+		urlMap := &compute.UrlMap{}
+		if ok, err := decodeAsset(result, urlMap); !ok {
+			return list, err
+		}
+		list.Merge((&loadBalancerProvider{id: d.id}).urlMapResources(project, urlMap))
+	case "storage.googleapis.com/Bucket":
+		bucket := &storage.Bucket{}
+		if ok, err := decodeAsset(result, bucket); !ok {
+			return list, err
+		}
+		list.Append((&cloudStorageProvider{id: d.id}).bucketResource(bucket, public))
+	case "cloudfunctions.googleapis.com/CloudFunction":
+		function := &cloudfunctions.CloudFunction{}
+		if ok, err := decodeAsset(result, function); !ok || function.HttpsTrigger == nil {
+			return list, err
+		}
+		list.Append((&cloudFunctionsProvider{id: d.id}).functionResource(function, public))
+	case "run.googleapis.com/Service":
+		service := &run.Service{}
+		if ok, err := decodeAsset(result, service); !ok || service.Status == nil {
+			return list, err
+		}
+		list.Append((&cloudRunProvider{id: d.id}).serviceResource(service, public))
+	case "sqladmin.googleapis.com/Instance":
+		instance := &sqladmin.DatabaseInstance{}
+		if ok, err := decodeAsset(result, instance); !ok {
+			return list, err
+		}
+		list.Merge((&cloudSQLProvider{id: d.id}).instanceResources(project, instance))
+	}
+	return list, nil
+}
+
+// decodeAsset decodes the resource data of an asset, the v1 version being
+// preferred when several versions are returned. It returns false if the
+// asset has no resource data.
+func decodeAsset(result *cloudasset.ResourceSearchResult, v interface{}) (bool, error) {
+	var data []byte
+	for _, versioned := range result.VersionedResources {
+		if data == nil || versioned.Version == "v1" {
+			data = versioned.Resource
+		}
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// assetProject returns the project id of an asset from its full resource
+// name, or the project number for the assets not named after their project
+func assetProject(result *cloudasset.ResourceSearchResult) string {
+	if _, after, ok := strings.Cut(result.Name, "/projects/"); ok {
+		project, _, _ := strings.Cut(after, "/")
+		return project
+	}
+	return strings.TrimPrefix(result.Project, "projects/")
+}
@@ -12,6 +12,7 @@ type cloudStorageProvider struct {
 	id       string
 	storage  *storage.Service
 	projects []string
+	report   *projectErrorReport
 }
 
 func (d *cloudStorageProvider) name() string {
@@ -27,26 +28,33 @@ func (d *cloudStorageProvider) GetResource(ctx context.Context) (*schema.Resourc
 		return nil, fmt.Errorf("could not get buckets: %s", err)
 	}
 	for _, bucket := range buckets {
-		resource := &schema.Resource{
-			ID:       d.id,
-			Provider: providerName,
-			DNSName:  fmt.Sprintf("%s.storage.googleapis.com", bucket.Name),
-			Public:   d.isBucketPublic(bucket.Name),
-			Service:  d.name(),
-		}
-		list.Append(resource)
+		list.Append(d.bucketResource(bucket, d.isBucketPublic(bucket.Name)))
 	}
 	return list, nil
 }
 
+// bucketResource returns the hostname of a bucket
+func (d *cloudStorageProvider) bucketResource(bucket *storage.Bucket, public bool) *schema.Resource {
+	return &schema.Resource{
+		ID:       d.id,
+		Provider: providerName,
+		DNSName:  fmt.Sprintf("%s.storage.googleapis.com", bucket.Name),
+		Public:   public,
+		Service:  d.name(),
+	}
+}
+
 func (d *cloudStorageProvider) getBuckets() ([]*storage.Bucket, error) {
 	var buckets []*storage.Bucket
 	for _, project := range d.projects {
 		bucketsService := d.storage.Buckets.List(project)
-		_ = bucketsService.Pages(context.Background(), func(bal *storage.Buckets) error {
+		err := bucketsService.Pages(context.Background(), func(bal *storage.Buckets) error {
 			buckets = append(buckets, bal.Items...)
 			return nil
 		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
 	}
 	return buckets, nil
 }
@@ -13,6 +13,7 @@ type cloudRunProvider struct {
 	id       string
 	run      *run.APIService
 	projects []string
+	report   *projectErrorReport
 }
 
 func (d *cloudRunProvider) name() string {
@@ -28,25 +29,30 @@ func (d *cloudRunProvider) GetResource(ctx context.Context) (*schema.Resources,
 	}
 	
 	for _, service := range services {
-		serviceUrl, _ := url.Parse(service.Status.Url)
-		resource := &schema.Resource{
-			ID:       d.id,
-			Provider: providerName,
-			DNSName:  serviceUrl.Hostname(),
-			Public:   d.isPublicService(service.Metadata.Name),
-			Service:  d.name(),
-		}
-		list.Append(resource)
+		list.Append(d.serviceResource(service, d.isPublicService(service.Metadata.Name)))
 	}
 	return list, nil
 }
 
+// serviceResource returns the hostname of the url of a service
+func (d *cloudRunProvider) serviceResource(service *run.Service, public bool) *schema.Resource {
+	serviceUrl, _ := url.Parse(service.Status.Url)
+	return &schema.Resource{
+		ID:       d.id,
+		Provider: providerName,
+		DNSName:  serviceUrl.Hostname(),
+		Public:   public,
+		Service:  d.name(),
+	}
+}
+
 func (d *cloudRunProvider) getServices() ([]*run.Service, error) {
 	var services []*run.Service
 	for _, project := range d.projects {
 		locationsService := d.run.Projects.Locations.List(fmt.Sprintf("projects/%s", project))
 		locationsResponse, err := locationsService.Do()
 		if err != nil {
+			d.report.record(project, d.name(), err)
 			continue
 		}
 
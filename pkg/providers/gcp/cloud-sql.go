@@ -0,0 +1,91 @@
+package gcp
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	sqladmin "google.golang.org/api/sqladmin/v1"
+)
+
+// cloudSQLProvider is a provider for gcp Cloud SQL instances
+type cloudSQLProvider struct {
+	id       string
+	sqladmin *sqladmin.Service
+	projects []string
+	report   *projectErrorReport
+}
+
+func (d *cloudSQLProvider) name() string {
+	return "cloud-sql"
+}
+
+// GetResource returns all the Cloud SQL resources in the store for a provider.
+func (d *cloudSQLProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, project := range d.projects {
+		err := d.sqladmin.Instances.List(project).Pages(ctx, func(instances *sqladmin.InstancesListResponse) error {
+			for _, instance := range instances.Items {
+				list.Merge(d.instanceResources(project, instance))
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+	}
+	return list, nil
+}
+
+// instanceResources returns the primary, private and outgoing addresses of an instance
+func (d *cloudSQLProvider) instanceResources(project string, instance *sqladmin.DatabaseInstance) *schema.Resources {
+	list := schema.NewResources()
+
+	metadata := map[string]string{
+		"project":          project,
+		"region":           instance.Region,
+		"name":             instance.Name,
+		"connection_name":  instance.ConnectionName,
+		"database_version": instance.DatabaseVersion,
+		"state":            instance.State,
+	}
+	if instance.Settings != nil && instance.Settings.IpConfiguration != nil {
+		ipConfig := instance.Settings.IpConfiguration
+		networks := make([]string, 0, len(ipConfig.AuthorizedNetworks))
+		for _, network := range ipConfig.AuthorizedNetworks {
+			networks = append(networks, network.Value)
+		}
+		metadata["authorized_networks"] = strings.Join(networks, ",")
+		metadata["require_ssl"] = strconv.FormatBool(ipConfig.RequireSsl)
+	}
+	metadata = cleanMetadata(metadata)
+
+	for _, address := range instance.IpAddresses {
+		resource := &schema.Resource{
+			ID:       d.id,
+			Provider: providerName,
+			Service:  d.name(),
+			Metadata: metadata,
+		}
+		if address.Type == "PRIVATE" {
+			resource.PrivateIpv4 = address.IpAddress
+		} else {
+			resource.Public = true
+			resource.PublicIPv4 = address.IpAddress
+		}
+		list.Append(resource)
+	}
+	if instance.Ipv6Address != "" {
+		list.Append(&schema.Resource{
+			ID:         d.id,
+			Public:     true,
+			PublicIPv6: instance.Ipv6Address,
+			Provider:   providerName,
+			Service:    d.name(),
+			Metadata:   metadata,
+		})
+	}
+	return list
+}
@@ -2,7 +2,6 @@ package gcp
 
 import (
 	"context"
-	"log"
 
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	"google.golang.org/api/dns/v1"
@@ -13,6 +12,7 @@ type cloudDNSProvider struct {
 	id       string
 	dns      *dns.Service
 	projects []string
+	report   *projectErrorReport
 }
 
 func (d *cloudDNSProvider) name() string {
@@ -34,14 +34,13 @@ func (d *cloudDNSProvider) GetResource(ctx context.Context) (*schema.Resources,
 					return nil
 				})
 				if err != nil {
-					log.Printf("Could not get resource_records for zone %s in project %s: %s\n", z.Name, project, err)
-					continue
+					d.report.record(project, d.name(), err)
 				}
 			}
 			return nil
 		})
 		if err != nil {
-			log.Printf("Could not get all zones for project %s: %s\n", project, err)
+			d.report.record(project, d.name(), err)
 			continue
 		}
 	}
@@ -0,0 +1,94 @@
+package gcp
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/projectdiscovery/gologger"
+	"google.golang.org/api/googleapi"
+)
+
+// projectErrorReport collects errors per project and service so that
+// a disabled API in one project does not hide results from the others.
+type projectErrorReport struct {
+	mu     sync.Mutex
+	errors map[string]map[string]string
+}
+
+func newProjectErrorReport() *projectErrorReport {
+	return &projectErrorReport{errors: make(map[string]map[string]string)}
+}
+
+// record stores the error returned by a service for a project
+func (r *projectErrorReport) record(project, service string, err error) {
+	if r == nil || err == nil {
+		return
+	}
+	message := err.Error()
+	if isAPIDisabled(err) {
+		message = "API disabled"
+	} else if isPermissionDenied(err) {
+		message = "permission denied"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.errors[project]; !ok {
+		r.errors[project] = make(map[string]string)
+	}
+	r.errors[project][service] = message
+}
+
+// log prints the collected errors grouped by project
+func (r *projectErrorReport) log() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	projects := make([]string, 0, len(r.errors))
+	for project := range r.errors {
+		projects = append(projects, project)
+	}
+	sort.Strings(projects)
+
+	for _, project := range projects {
+		services := make([]string, 0, len(r.errors[project]))
+		for service, message := range r.errors[project] {
+			services = append(services, service+" ("+message+")")
+		}
+		sort.Strings(services)
+		gologger.Warning().Msgf("Could not enumerate gcp project %s: %s", project, strings.Join(services, ", "))
+	}
+}
+
+// isAPIDisabled returns true if the error was caused by an API that
+// is not enabled in the project.
+func isAPIDisabled(err error) bool {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != http.StatusForbidden {
+		return false
+	}
+	for _, item := range apiErr.Errors {
+		if item.Reason == "accessNotConfigured" {
+			return true
+		}
+	}
+	return strings.Contains(apiErr.Message, "SERVICE_DISABLED") || strings.Contains(apiErr.Message, "has not been used in project")
+}
+
+// isPermissionDenied returns true if the credentials lack access to the project
+func isPermissionDenied(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusForbidden
+}
+
+// isNotFound returns true if the requested resource does not exist
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,87 @@
+package gcp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	firebasehosting "google.golang.org/api/firebasehosting/v1beta1"
+)
+
+// firebaseHostingProvider is a provider for gcp Firebase Hosting sites and their domains
+type firebaseHostingProvider struct {
+	id       string
+	hosting  *firebasehosting.Service
+	projects []string
+	report   *projectErrorReport
+}
+
+func (d *firebaseHostingProvider) name() string {
+	return "firebase-hosting"
+}
+
+// GetResource returns all the Firebase Hosting resources in the store for a provider.
+func (d *firebaseHostingProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, project := range d.projects {
+		err := d.hosting.Projects.Sites.List(fmt.Sprintf("projects/%s", project)).Pages(ctx, func(sites *firebasehosting.ListSitesResponse) error {
+			for _, site := range sites.Sites {
+				siteName := lastSegment(site.Name)
+				if defaultURL, err := url.Parse(site.DefaultUrl); err == nil {
+					list.Append(d.hostResource(defaultURL.Hostname(), map[string]string{
+						"project":   project,
+						"site":      siteName,
+						"site_type": site.Type,
+						"app_id":    site.AppId,
+					}))
+				}
+
+				// domains include the web.app and firebaseapp.com names along with the custom ones
+				err := d.hosting.Projects.Sites.Domains.List(site.Name).Pages(ctx, func(domains *firebasehosting.ListDomainsResponse) error {
+					for _, domain := range domains.Domains {
+						metadata := map[string]string{
+							"project":       project,
+							"site":          siteName,
+							"status":        domain.Status,
+							"custom_domain": strconv.FormatBool(isCustomFirebaseDomain(domain.DomainName)),
+						}
+						if domain.DomainRedirect != nil {
+							metadata["redirect"] = domain.DomainRedirect.DomainName
+						}
+						list.Append(d.hostResource(domain.DomainName, metadata))
+					}
+					return nil
+				})
+				if err != nil {
+					d.report.record(project, d.name(), err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+	}
+	return list, nil
+}
+
+func (d *firebaseHostingProvider) hostResource(host string, metadata map[string]string) *schema.Resource {
+	return &schema.Resource{
+		ID:       d.id,
+		Public:   true,
+		DNSName:  host,
+		Provider: providerName,
+		Service:  d.name(),
+		Metadata: cleanMetadata(metadata),
+	}
+}
+
+// isCustomFirebaseDomain returns false for the default web.app and
+// firebaseapp.com names firebase assigns to a site
+func isCustomFirebaseDomain(domain string) bool {
+	return !strings.HasSuffix(domain, ".web.app") && !strings.HasSuffix(domain, ".firebaseapp.com")
+}
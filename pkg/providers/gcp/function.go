@@ -13,6 +13,7 @@ type cloudFunctionsProvider struct {
 	id        string
 	functions *cloudfunctions.Service
 	projects  []string
+	report    *projectErrorReport
 }
 
 func (d *cloudFunctionsProvider) name() string {
@@ -27,27 +28,37 @@ func (d *cloudFunctionsProvider) GetResource(ctx context.Context) (*schema.Resou
 		return nil, fmt.Errorf("could not get functions: %s", err)
 	}
 	for _, function := range functions {
-		funcUrl, _ := url.Parse(function.HttpsTrigger.Url)
-		resource := &schema.Resource{
-			ID:       d.id,
-			Provider: providerName,
-			DNSName:  funcUrl.Hostname(),
-			Public:   d.isPublicFunction(function.Name),
-			Service:  d.name(),
+		if function.HttpsTrigger == nil {
+			continue
 		}
-		list.Append(resource)
+		list.Append(d.functionResource(function, d.isPublicFunction(function.Name)))
 	}
 	return list, nil
 }
 
+// functionResource returns the hostname of the https trigger of a function
+func (d *cloudFunctionsProvider) functionResource(function *cloudfunctions.CloudFunction, public bool) *schema.Resource {
+	funcUrl, _ := url.Parse(function.HttpsTrigger.Url)
+	return &schema.Resource{
+		ID:       d.id,
+		Provider: providerName,
+		DNSName:  funcUrl.Hostname(),
+		Public:   public,
+		Service:  d.name(),
+	}
+}
+
 func (d *cloudFunctionsProvider) getFunctions() ([]*cloudfunctions.CloudFunction, error) {
 	var functions []*cloudfunctions.CloudFunction
 	for _, project := range d.projects {
 		functionsService := d.functions.Projects.Locations.Functions.List(fmt.Sprintf("projects/%s/locations/-", project))
-		_ = functionsService.Pages(context.Background(), func(fal *cloudfunctions.ListFunctionsResponse) error {
+		err := functionsService.Pages(context.Background(), func(fal *cloudfunctions.ListFunctionsResponse) error {
 			functions = append(functions, fal.Functions...)
 			return nil
 		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
 	}
 	return functions, nil
 }
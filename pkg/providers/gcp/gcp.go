@@ -7,12 +7,17 @@ import (
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	"github.com/projectdiscovery/gologger"
 	errorutil "github.com/projectdiscovery/utils/errors"
+	apigateway "google.golang.org/api/apigateway/v1"
+	"google.golang.org/api/appengine/v1"
+	cloudasset "google.golang.org/api/cloudasset/v1"
 	"google.golang.org/api/cloudfunctions/v1"
-	"google.golang.org/api/cloudresourcemanager/v1"
 	"google.golang.org/api/compute/v1"
 	container "google.golang.org/api/container/v1beta1"
 	"google.golang.org/api/dns/v1"
+	firebasehosting "google.golang.org/api/firebasehosting/v1beta1"
 	run "google.golang.org/api/run/v1"
+	"google.golang.org/api/servicemanagement/v1"
+	sqladmin "google.golang.org/api/sqladmin/v1"
 	"google.golang.org/api/storage/v1"
 )
 
@@ -24,12 +29,26 @@ type Provider struct {
 	storage   *storage.Service
 	functions *cloudfunctions.Service
 	run       *run.APIService
+	appengine *appengine.APIService
+	hosting   *firebasehosting.Service
+	sqladmin  *sqladmin.Service
+	gateway   *apigateway.Service
+	endpoints *servicemanagement.APIService
+	asset     *cloudasset.Service
 	services  schema.ServiceMap
-	id        string
-	projects  []string
+	// assetScope and assetServices are the scope searched with cloud asset
+	// inventory and the services listed from it instead of their own apis
+	assetScope    string
+	assetServices schema.ServiceMap
+	// assetProjects are the numbers of the projects the assets are kept
+	// for when the projects are scoped, all the assets being kept if nil
+	assetProjects map[string]struct{}
+	id            string
+	projects      []string
+	report        *projectErrorReport
 }
 
-var Services = []string{"dns", "gke", "compute", "s3", "cloud-function", "cloud-run"}
+var Services = []string{"dns", "gke", "compute", "load-balancer", "address", "s3", "cloud-function", "cloud-run", "app-engine", "firebase-hosting", "cloud-sql", "api-gateway"}
 
 const serviceAccountJSON = "gcp_service_account_key"
 const providerName = "gcp"
@@ -81,6 +100,31 @@ func New(options schema.OptionBlock) (*Provider, error) {
 	if err != nil {
 		return nil, errorutil.NewWithErr(err).Msgf("could not register gcp service account")
 	}
+
+	// services are looked up in the resources of the asset scope when set,
+	// the others still being listed from their apis
+	provider.assetServices = make(schema.ServiceMap)
+	if scope, ok := options.GetMetadata(cloudAssetScope); ok && scope != "" {
+		assetService, err := cloudasset.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create cloud asset service with api key")
+		}
+		provider.asset = assetService
+		provider.assetScope = scope
+		for service := range services {
+			if _, ok := assetTypes[service]; ok {
+				provider.assetServices[service] = struct{}{}
+			}
+		}
+	}
+	direct := make(schema.ServiceMap)
+	for service := range services {
+		if !provider.assetServices.Has(service) {
+			direct[service] = struct{}{}
+		}
+	}
+	services = direct
+
 	if services.Has("dns") {
 		dnsService, err := dns.NewService(context.Background(), creds)
 		if err != nil {
@@ -88,7 +132,7 @@ func New(options schema.OptionBlock) (*Provider, error) {
 		}
 		provider.dns = dnsService
 	}
-	if services.Has("compute") {
+	if services.Has("compute") || services.Has("load-balancer") || services.Has("address") {
 		computeService, err := compute.NewService(context.Background(), creds)
 		if err != nil {
 			return nil, errorutil.NewWithErr(err).Msgf("could not create compute service with api key")
@@ -127,28 +171,85 @@ func New(options schema.OptionBlock) (*Provider, error) {
 		provider.run = cloudRunService
 	}
 
-	projects := []string{}
-	manager, err := cloudresourcemanager.NewService(context.Background(), creds)
-	if err != nil {
-		return nil, errorutil.NewWithErr(err).Msgf("could not list projects")
+	if services.Has("app-engine") {
+		appEngineService, err := appengine.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create app engine service with api key")
+		}
+		provider.appengine = appEngineService
 	}
-	list := manager.Projects.List()
-	err = list.Pages(context.Background(), func(resp *cloudresourcemanager.ListProjectsResponse) error {
-		for _, project := range resp.Projects {
-			projects = append(projects, project.ProjectId)
+
+	if services.Has("firebase-hosting") {
+		hostingService, err := firebasehosting.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create firebase hosting service with api key")
 		}
-		return nil
-	})
-	provider.projects = projects
-	return provider, err
+		provider.hosting = hostingService
+	}
+
+	if services.Has("cloud-sql") {
+		sqlAdminService, err := sqladmin.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create cloud sql service with api key")
+		}
+		provider.sqladmin = sqlAdminService
+	}
+
+	if services.Has("api-gateway") {
+		gatewayService, err := apigateway.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create api gateway service with api key")
+		}
+		provider.gateway = gatewayService
+
+		endpointsService, err := servicemanagement.NewService(context.Background(), creds)
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not create service management service with api key")
+		}
+		provider.endpoints = endpointsService
+	}
+
+	// projects are needed by the services listed from their apis, and to
+	// restrict the assets of the scope to the configured projects
+	scope := parseProjectScope(options)
+	filterAssets := provider.asset != nil && scope.isSet()
+	if len(direct) > 0 || filterAssets {
+		projects, err := scope.resolveProjects(context.Background(), creds)
+		if err != nil {
+			return nil, err
+		}
+		if len(direct) > 0 {
+			provider.projects = projectIDList(projects)
+		}
+		if filterAssets {
+			provider.assetProjects, err = projectNumbers(context.Background(), creds, projects)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	provider.report = newProjectErrorReport()
+	return provider, nil
 }
 
 // Resources returns the provider for an resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
+	// the projects that failed are reported even when a service aborts the run
+	defer p.report.log()
+
 	finalResources := schema.NewResources()
 
+	if p.asset != nil {
+		assetProvider := &cloudAssetProvider{id: p.id, asset: p.asset, scope: p.assetScope, services: p.assetServices, projects: p.assetProjects}
+		assetData, err := assetProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(assetData)
+	}
+
 	if p.dns != nil {
-		cloudDNSProvider := &cloudDNSProvider{dns: p.dns, id: p.id, projects: p.projects}
+		cloudDNSProvider := &cloudDNSProvider{dns: p.dns, id: p.id, projects: p.projects, report: p.report}
 		zones, err := cloudDNSProvider.GetResource(ctx)
 		if err != nil {
 			return nil, err
@@ -157,7 +258,7 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 	}
 
 	if p.gke != nil {
-		GKEProvider := &gkeProvider{svc: p.gke, id: p.id, projects: p.projects}
+		GKEProvider := &gkeProvider{svc: p.gke, id: p.id, projects: p.projects, report: p.report}
 		gkeData, err := GKEProvider.GetResource(ctx)
 		if err != nil {
 			gologger.Warning().Msgf("Could not get GKE resources: %s\n", err)
@@ -165,8 +266,8 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 		finalResources.Merge(gkeData)
 	}
 
-	if p.compute != nil {
-		VMProvider := &cloudVMProvider{compute: p.compute, id: p.id, projects: p.projects}
+	if p.compute != nil && p.listsDirectly("compute") {
+		VMProvider := &cloudVMProvider{compute: p.compute, id: p.id, projects: p.projects, report: p.report}
 		vmData, err := VMProvider.GetResource(ctx)
 		if err != nil {
 			return nil, err
@@ -174,8 +275,26 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 		finalResources.Merge(vmData)
 	}
 
+	if p.compute != nil && p.listsDirectly("load-balancer") {
+		loadBalancerProvider := &loadBalancerProvider{compute: p.compute, id: p.id, projects: p.projects, report: p.report}
+		loadBalancerData, err := loadBalancerProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(loadBalancerData)
+	}
+
+	if p.compute != nil && p.listsDirectly("address") {
+		addressProvider := &addressProvider{compute: p.compute, id: p.id, projects: p.projects, report: p.report}
+		addressData, err := addressProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(addressData)
+	}
+
 	if p.storage != nil {
-		cloudStorageProvider := &cloudStorageProvider{id: p.id, storage: p.storage, projects: p.projects}
+		cloudStorageProvider := &cloudStorageProvider{id: p.id, storage: p.storage, projects: p.projects, report: p.report}
 		storageData, err := cloudStorageProvider.GetResource(ctx)
 		if err != nil {
 			return nil, err
@@ -184,7 +303,7 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 	}
 
 	if p.functions != nil {
-		cloudFunctionsProvider := &cloudFunctionsProvider{id: p.id, functions: p.functions, projects: p.projects}
+		cloudFunctionsProvider := &cloudFunctionsProvider{id: p.id, functions: p.functions, projects: p.projects, report: p.report}
 		functionsData, err := cloudFunctionsProvider.GetResource(ctx)
 		if err != nil {
 			return nil, err
@@ -193,7 +312,7 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 	}
 
 	if p.run != nil {
-		cloudRunProvider := &cloudRunProvider{id: p.id, run: p.run, projects: p.projects}
+		cloudRunProvider := &cloudRunProvider{id: p.id, run: p.run, projects: p.projects, report: p.report}
 		cloudRunData, err := cloudRunProvider.GetResource(ctx)
 		if err != nil {
 			return nil, err
@@ -201,11 +320,59 @@ func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
 		finalResources.Merge(cloudRunData)
 	}
 
+	if p.appengine != nil {
+		appEngineProvider := &appEngineProvider{id: p.id, appengine: p.appengine, projects: p.projects, report: p.report}
+		appEngineData, err := appEngineProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(appEngineData)
+	}
+
+	if p.hosting != nil {
+		firebaseHostingProvider := &firebaseHostingProvider{id: p.id, hosting: p.hosting, projects: p.projects, report: p.report}
+		hostingData, err := firebaseHostingProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(hostingData)
+	}
+
+	if p.sqladmin != nil {
+		cloudSQLProvider := &cloudSQLProvider{id: p.id, sqladmin: p.sqladmin, projects: p.projects, report: p.report}
+		sqlData, err := cloudSQLProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(sqlData)
+	}
+
+	if p.gateway != nil {
+		apiGatewayProvider := &apiGatewayProvider{id: p.id, apigateway: p.gateway, servicemanagement: p.endpoints, projects: p.projects, report: p.report}
+		gatewayData, err := apiGatewayProvider.GetResource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		finalResources.Merge(gatewayData)
+	}
+
 	return finalResources, nil
 }
 
+// listsDirectly returns true if a service is enabled and listed from its own api
+func (p *Provider) listsDirectly(service string) bool {
+	return p.services.Has(service) && !p.assetServices.Has(service)
+}
+
 // Verify checks if the GCP provider credentials are valid
 func (p *Provider) Verify(ctx context.Context) error {
+	if p.asset != nil {
+		_, err := p.asset.V1.SearchAllResources(p.assetScope).PageSize(1).Context(ctx).Do()
+		if err != nil {
+			return errorutil.NewWithErr(err).Msgf("failed to verify cloud asset access")
+		}
+		return nil
+	}
 	if len(p.projects) == 0 {
 		return errorutil.New("no accessible GCP projects found with provided credentials")
 	}
@@ -251,3 +418,13 @@ func (p *Provider) Verify(ctx context.Context) error {
 	}
 	return errorutil.New("no accessible GCP services found with provided credentials")
 }
+
+// cleanMetadata removes the empty values from the metadata of a resource
+func cleanMetadata(metadata map[string]string) map[string]string {
+	for key, value := range metadata {
+		if value == "" {
+			delete(metadata, key)
+		}
+	}
+	return metadata
+}
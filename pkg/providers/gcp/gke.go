@@ -4,23 +4,25 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"strconv"
+	"strings"
 
-	"github.com/pkg/errors"
 	"github.com/projectdiscovery/cloudlist/pkg/providers/k8s"
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	container "google.golang.org/api/container/v1beta1"
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
-
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
 )
 
-// gkeProvider is a provider for aws Route53 API
+// gkeProvider is a provider for gcp GKE clusters
 type gkeProvider struct {
 	id       string
 	svc      *container.Service
 	projects []string
+	report   *projectErrorReport
 }
 
 func (d *gkeProvider) name() string {
@@ -32,75 +34,159 @@ func (d *gkeProvider) GetResource(ctx context.Context) (*schema.Resources, error
 	list := schema.NewResources()
 
 	for _, project := range d.projects {
-		kubeConfig, err := d.getK8sClusterConfigs(ctx, project)
+		// the - location lists zonal and regional clusters
+		resp, err := d.svc.Projects.Locations.Clusters.List(fmt.Sprintf("projects/%s/locations/-", project)).Context(ctx).Do()
 		if err != nil {
-			return nil, err
+			d.report.record(project, d.name(), err)
+			continue
 		}
-		// Just list all the namespaces found in the project to test the API.
-		for clusterName := range kubeConfig.Clusters {
-			cfg, err := clientcmd.NewNonInteractiveClientConfig(*kubeConfig, clusterName, &clientcmd.ConfigOverrides{CurrentContext: clusterName}, nil).ClientConfig()
-			if err != nil {
-				return nil, fmt.Errorf("failed to create Kubernetes configuration cluster=%s: %w", clusterName, err)
-			}
+		for _, cluster := range resp.Clusters {
+			list.Merge(d.endpointResources(project, cluster))
 
-			k8sClient, err := kubernetes.NewForConfig(cfg)
+			// an unreachable cluster does not stop the others from being listed
+			clusterData, err := d.clusterResources(ctx, project, cluster)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create Kubernetes client cluster=%s: %w", clusterName, err)
-			}
-			timeoutSeconds := int64(10)
-			ingress, err := k8sClient.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{
-				TimeoutSeconds: &timeoutSeconds,
-			})
-			if err != nil {
-				return nil, errors.Wrap(err, "could not list kubernetes ingress")
-			}
-			k8sIngressProvider := k8s.NewK8sIngressProvider(d.id, ingress)
-			ingressHosts, _ := k8sIngressProvider.GetResource(ctx)
-			for _, ingressHost := range ingressHosts.Items {
-				ingressHost.Service = d.name()
+				d.report.record(project, d.name()+"/"+cluster.Name, err)
+				continue
 			}
-			list.Merge(ingressHosts)
+			list.Merge(clusterData)
 		}
 	}
 	return list, nil
 }
 
-func (d *gkeProvider) getK8sClusterConfigs(ctx context.Context, projectId string) (*api.Config, error) {
-	// Basic config structure
-	ret := api.Config{
-		APIVersion: "v1",
-		Kind:       "Config",
-		Clusters:   map[string]*api.Cluster{},  // Clusters is a map of referencable names to cluster configs
-		AuthInfos:  map[string]*api.AuthInfo{}, // AuthInfos is a map of referencable names to user configs
-		Contexts:   map[string]*api.Context{},  // Contexts is a map of referencable names to context configs
+// endpointResources returns the control plane endpoints of a cluster along
+// with the networks authorized to reach them
+func (d *gkeProvider) endpointResources(project string, cluster *container.Cluster) *schema.Resources {
+	list := schema.NewResources()
+
+	publicEndpoint, privateEndpoint := cluster.Endpoint, ""
+	if config := cluster.PrivateClusterConfig; config != nil {
+		privateEndpoint = config.PrivateEndpoint
+		if config.PublicEndpoint != "" {
+			publicEndpoint = config.PublicEndpoint
+		}
+		if config.EnablePrivateEndpoint {
+			publicEndpoint = ""
+		}
 	}
 
-	// Ask Google for a list of all kube clusters in the given project.
-	resp, err := d.svc.Projects.Zones.Clusters.List(projectId, "-").Context(ctx).Do()
+	metadata := d.clusterMetadata(project, cluster)
+	metadata["resource_type"] = "control_plane"
+	metadata["private_endpoint"] = privateEndpoint
+	authorizedNetworks := false
+	if config := cluster.MasterAuthorizedNetworksConfig; config != nil && config.Enabled {
+		authorizedNetworks = true
+		cidrs := make([]string, 0, len(config.CidrBlocks))
+		for _, block := range config.CidrBlocks {
+			cidrs = append(cidrs, block.CidrBlock)
+		}
+		metadata["authorized_networks"] = strings.Join(cidrs, ",")
+	}
+	metadata["authorized_networks_enabled"] = strconv.FormatBool(authorizedNetworks)
+	metadata = cleanMetadata(metadata)
+
+	if publicEndpoint != "" {
+		list.Append(&schema.Resource{
+			ID:         d.id,
+			Public:     true,
+			PublicIPv4: publicEndpoint,
+			Provider:   providerName,
+			Service:    d.name(),
+			Metadata:   metadata,
+		})
+	}
+	if privateEndpoint != "" {
+		list.Append(&schema.Resource{
+			ID:          d.id,
+			PrivateIpv4: privateEndpoint,
+			Provider:    providerName,
+			Service:     d.name(),
+			Metadata:    metadata,
+		})
+	}
+	return list
+}
+
+// clusterResources returns the services, ingresses and node addresses of a cluster
+func (d *gkeProvider) clusterResources(ctx context.Context, project string, cluster *container.Cluster) (*schema.Resources, error) {
+	clientSet, err := d.clusterClient(cluster)
 	if err != nil {
-		return nil, fmt.Errorf("clusters list project=%s: %w", projectId, err)
+		return nil, err
+	}
+	services := make(schema.ServiceMap)
+	for _, service := range k8s.Services {
+		services[service] = struct{}{}
+	}
+	list, err := k8s.ClusterResources(ctx, clientSet, d.id, services)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range list.Items {
+		metadata := d.clusterMetadata(project, cluster)
+		metadata["resource_type"] = item.Service
+		item.Provider = providerName
+		item.Service = d.name()
+		item.Metadata = cleanMetadata(metadata)
 	}
 
-	for _, f := range resp.Clusters {
-		name := fmt.Sprintf("gke_%s_%s_%s", projectId, f.Zone, f.Name)
-		cert, err := base64.StdEncoding.DecodeString(f.MasterAuth.ClusterCaCertificate)
-		if err != nil {
-			return nil, fmt.Errorf("invalid certificate cluster=%s cert=%s: %w", name, f.MasterAuth.ClusterCaCertificate, err)
-		}
-		// example: gke_my-project_us-central1-b_cluster-1 => https://XX.XX.XX.XX
-		ret.Clusters[name] = &api.Cluster{
-			CertificateAuthorityData: cert,
-			Server:                   "https://" + f.Endpoint,
-		}
-		// Just reuse the context name as an auth name.
-		ret.Contexts[name] = &api.Context{
-			Cluster:  name,
-			AuthInfo: name,
-		}
-		// GCP specific configation;
-		ret.AuthInfos[name] = &api.AuthInfo{
-			AuthProvider: &api.AuthProviderConfig{Name: googleAuthPlugin},
+	nodes, err := clientSet.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("could not list nodes cluster=%s: %w", cluster.Name, err)
+	}
+	for _, node := range nodes.Items {
+		metadata := d.clusterMetadata(project, cluster)
+		metadata["resource_type"] = "node"
+		metadata["node_name"] = node.Name
+		metadata = cleanMetadata(metadata)
+
+		for _, address := range node.Status.Addresses {
+			resource := &schema.Resource{
+				ID:       d.id,
+				Provider: providerName,
+				Service:  d.name(),
+				Metadata: metadata,
+			}
+			switch address.Type {
+			case v1.NodeExternalIP:
+				resource.Public = true
+				resource.PublicIPv4 = address.Address
+			case v1.NodeInternalIP:
+				resource.PrivateIpv4 = address.Address
+			default:
+				continue
+			}
+			list.Append(resource)
 		}
 	}
-	return &ret, nil
+	return list, nil
+}
+
+// clusterClient returns a kubernetes client authenticated with the gcp credentials
+func (d *gkeProvider) clusterClient(cluster *container.Cluster) (*kubernetes.Clientset, error) {
+	if cluster.MasterAuth == nil {
+		return nil, fmt.Errorf("no master auth cluster=%s", cluster.Name)
+	}
+	cert, err := base64.StdEncoding.DecodeString(cluster.MasterAuth.ClusterCaCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate cluster=%s: %w", cluster.Name, err)
+	}
+	clientSet, err := kubernetes.NewForConfig(&rest.Config{
+		Host:            "https://" + cluster.Endpoint,
+		TLSClientConfig: rest.TLSClientConfig{CAData: cert},
+		AuthProvider:    &api.AuthProviderConfig{Name: googleAuthPlugin},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client cluster=%s: %w", cluster.Name, err)
+	}
+	return clientSet, nil
+}
+
+func (d *gkeProvider) clusterMetadata(project string, cluster *container.Cluster) map[string]string {
+	return map[string]string{
+		"project":  project,
+		"location": cluster.Location,
+		"cluster":  cluster.Name,
+		"status":   cluster.Status,
+	}
 }
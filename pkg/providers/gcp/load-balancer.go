@@ -0,0 +1,149 @@
+package gcp
+
+import (
+	"context"
+	"strings"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"google.golang.org/api/compute/v1"
+)
+
+// loadBalancerProvider is a provider for gcp forwarding rules and url maps
+type loadBalancerProvider struct {
+	id       string
+	compute  *compute.Service
+	projects []string
+	report   *projectErrorReport
+}
+
+func (d *loadBalancerProvider) name() string {
+	return "load-balancer"
+}
+
+// GetResource returns all the resources in the store for a provider.
+func (d *loadBalancerProvider) GetResource(ctx context.Context) (*schema.Resources, error) {
+	list := schema.NewResources()
+
+	for _, project := range d.projects {
+		err := d.compute.ForwardingRules.AggregatedList(project).Pages(ctx, func(rules *compute.ForwardingRuleAggregatedList) error {
+			for scope, scopedList := range rules.Items {
+				// global rules are listed below
+				if scope == "global" {
+					continue
+				}
+				for _, rule := range scopedList.ForwardingRules {
+					list.Append(d.forwardingRuleResource(project, rule))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+			continue
+		}
+
+		err = d.compute.GlobalForwardingRules.List(project).Pages(ctx, func(rules *compute.ForwardingRuleList) error {
+			for _, rule := range rules.Items {
+				list.Append(d.forwardingRuleResource(project, rule))
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+			continue
+		}
+
+		// url maps hold the hostnames served by http(s) load balancers
+		err = d.compute.UrlMaps.AggregatedList(project).Pages(ctx, func(urlMaps *compute.UrlMapsAggregatedList) error {
+			for _, scopedList := range urlMaps.Items {
+				for _, urlMap := range scopedList.UrlMaps {
+					list.Merge(d.urlMapResources(project, urlMap))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			d.report.record(project, d.name(), err)
+		}
+	}
+	return list, nil
+}
+
+// forwardingRuleResource returns the address of a forwarding rule with its ports and target
+func (d *loadBalancerProvider) forwardingRuleResource(project string, rule *compute.ForwardingRule) *schema.Resource {
+	ports := rule.PortRange
+	if len(rule.Ports) > 0 {
+		ports = strings.Join(rule.Ports, ",")
+	}
+	if rule.AllPorts {
+		ports = "all"
+	}
+	target := rule.Target
+	if target == "" {
+		target = rule.BackendService
+	}
+
+	resource := &schema.Resource{
+		ID:       d.id,
+		Public:   strings.HasPrefix(rule.LoadBalancingScheme, "EXTERNAL"),
+		Provider: providerName,
+		Service:  d.name(),
+		Metadata: cleanMetadata(map[string]string{
+			"project":               project,
+			"region":                regionOf(rule.Region),
+			"resource_type":         "forwarding_rule",
+			"name":                  rule.Name,
+			"protocol":              rule.IPProtocol,
+			"ports":                 ports,
+			"target":                lastSegment(target),
+			"load_balancing_scheme": rule.LoadBalancingScheme,
+		}),
+	}
+	if rule.IpVersion == "IPV6" || strings.Contains(rule.IPAddress, ":") {
+		resource.PublicIPv6 = strings.Split(rule.IPAddress, "/")[0]
+	} else {
+		resource.PublicIPv4 = rule.IPAddress
+	}
+	return resource
+}
+
+// urlMapResources returns the hostnames matched by the host rules of a url map
+func (d *loadBalancerProvider) urlMapResources(project string, urlMap *compute.UrlMap) *schema.Resources {
+	list := schema.NewResources()
+	for _, hostRule := range urlMap.HostRules {
+		for _, host := range hostRule.Hosts {
+			host = strings.TrimPrefix(host, "*.")
+			if host == "*" || host == "" {
+				continue
+			}
+			list.Append(&schema.Resource{
+				ID:       d.id,
+				Public:   true,
+				DNSName:  host,
+				Provider: providerName,
+				Service:  d.name(),
+				Metadata: cleanMetadata(map[string]string{
+					"project":       project,
+					"region":        regionOf(urlMap.Region),
+					"resource_type": "url_map",
+					"name":          urlMap.Name,
+					"path_matcher":  hostRule.PathMatcher,
+				}),
+			})
+		}
+	}
+	return list
+}
+
+// regionOf returns the region name of a regional resource, global otherwise
+func regionOf(regionURL string) string {
+	if regionURL == "" {
+		return "global"
+	}
+	return lastSegment(regionURL)
+}
+
+// lastSegment returns the name at the end of a resource url
+func lastSegment(resourceURL string) string {
+	return resourceURL[strings.LastIndex(resourceURL, "/")+1:]
+}
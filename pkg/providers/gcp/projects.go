@@ -0,0 +1,266 @@
+package gcp
+
+import (
+	"context"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/projectdiscovery/gologger"
+	errorutil "github.com/projectdiscovery/utils/errors"
+	sliceutil "github.com/projectdiscovery/utils/slice"
+	crmv1 "google.golang.org/api/cloudresourcemanager/v1"
+	crmv3 "google.golang.org/api/cloudresourcemanager/v3"
+	"google.golang.org/api/option"
+)
+
+const (
+	projectIDs      = "project_ids"
+	organizationID  = "organization_id"
+	folderIDs       = "folder_ids"
+	includeProjects = "include_projects"
+	excludeProjects = "exclude_projects"
+	includeLabels   = "include_labels"
+	excludeLabels   = "exclude_labels"
+)
+
+// projectScope describes which projects the provider enumerates.
+type projectScope struct {
+	// projectIDs are explicitly pinned projects, discovery is skipped if set
+	projectIDs []string
+	// organizationID and folderIDs are the roots of the hierarchy walk
+	organizationID string
+	folderIDs      []string
+	// include/exclude are project id patterns (path.Match syntax)
+	include []string
+	exclude []string
+	// includeLabels/excludeLabels are key or key=value label selectors
+	includeLabels []string
+	excludeLabels []string
+}
+
+// project is a discovered project along with its labels
+type project struct {
+	id string
+	// number is the project number cloud asset inventory reports assets with
+	number string
+	labels map[string]string
+}
+
+func parseProjectScope(options map[string]string) *projectScope {
+	split := func(key string) []string {
+		value, ok := options[key]
+		if !ok || value == "" {
+			return nil
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		return sliceutil.Dedupe(items)
+	}
+	return &projectScope{
+		projectIDs:     split(projectIDs),
+		organizationID: strings.TrimPrefix(strings.TrimSpace(options[organizationID]), "organizations/"),
+		folderIDs:      split(folderIDs),
+		include:        split(includeProjects),
+		exclude:        split(excludeProjects),
+		includeLabels:  split(includeLabels),
+		excludeLabels:  split(excludeLabels),
+	}
+}
+
+// isSet returns true if any option restricts the enumerated projects
+func (s *projectScope) isSet() bool {
+	return len(s.projectIDs) > 0 || s.organizationID != "" || len(s.folderIDs) > 0 ||
+		len(s.include) > 0 || len(s.exclude) > 0 || len(s.includeLabels) > 0 || len(s.excludeLabels) > 0
+}
+
+// resolveProjects returns the projects to enumerate for the scope
+func (s *projectScope) resolveProjects(ctx context.Context, creds option.ClientOption) ([]project, error) {
+	if len(s.projectIDs) > 0 {
+		projects := make([]project, 0, len(s.projectIDs))
+		for _, id := range s.projectIDs {
+			projects = append(projects, project{id: id})
+		}
+		return projects, nil
+	}
+
+	var projects []project
+	var err error
+	if s.organizationID != "" || len(s.folderIDs) > 0 {
+		projects, err = s.walkHierarchy(ctx, creds)
+	} else {
+		projects, err = listVisibleProjects(ctx, creds)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved []project
+	seen := make(map[string]struct{})
+	for _, p := range projects {
+		if !s.matches(p) {
+			gologger.Debug().Msgf("Skipping gcp project %s: excluded by project filters", p.id)
+			continue
+		}
+		if _, ok := seen[p.id]; ok {
+			continue
+		}
+		seen[p.id] = struct{}{}
+		resolved = append(resolved, p)
+	}
+	return resolved, nil
+}
+
+// projectNumbers returns the numbers of the projects, looking up the ones
+// that were pinned by id
+func projectNumbers(ctx context.Context, creds option.ClientOption, projects []project) (map[string]struct{}, error) {
+	numbers := make(map[string]struct{})
+	var manager *crmv1.Service
+	for _, p := range projects {
+		if p.number == "" {
+			if manager == nil {
+				var err error
+				if manager, err = crmv1.NewService(ctx, creds); err != nil {
+					return nil, errorutil.NewWithErr(err).Msgf("could not create resource manager service")
+				}
+			}
+			resp, err := manager.Projects.Get(p.id).Context(ctx).Do()
+			if err != nil {
+				return nil, errorutil.NewWithErr(err).Msgf("could not get project %s", p.id)
+			}
+			p.number = strconv.FormatInt(resp.ProjectNumber, 10)
+		}
+		numbers[p.number] = struct{}{}
+	}
+	return numbers, nil
+}
+
+// projectIDList returns the ids of the projects
+func projectIDList(projects []project) []string {
+	ids := make([]string, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.id)
+	}
+	return ids
+}
+
+// listVisibleProjects lists every active project visible to the credentials
+func listVisibleProjects(ctx context.Context, creds option.ClientOption) ([]project, error) {
+	manager, err := crmv1.NewService(ctx, creds)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create resource manager service")
+	}
+
+	var projects []project
+	list := manager.Projects.List().Filter("lifecycleState:ACTIVE")
+	err = list.Pages(ctx, func(resp *crmv1.ListProjectsResponse) error {
+		for _, p := range resp.Projects {
+			projects = append(projects, project{id: p.ProjectId, number: strconv.FormatInt(p.ProjectNumber, 10), labels: p.Labels})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not list projects")
+	}
+	return projects, nil
+}
+
+// walkHierarchy discovers all projects below the configured organization
+// and folders, descending into nested folders.
+func (s *projectScope) walkHierarchy(ctx context.Context, creds option.ClientOption) ([]project, error) {
+	manager, err := crmv3.NewService(ctx, creds)
+	if err != nil {
+		return nil, errorutil.NewWithErr(err).Msgf("could not create resource manager service")
+	}
+
+	var parents []string
+	if s.organizationID != "" {
+		parents = append(parents, "organizations/"+s.organizationID)
+	}
+	for _, folder := range s.folderIDs {
+		parents = append(parents, "folders/"+strings.TrimPrefix(folder, "folders/"))
+	}
+
+	var projects []project
+	visited := make(map[string]struct{})
+	for len(parents) > 0 {
+		parent := parents[0]
+		parents = parents[1:]
+		if _, ok := visited[parent]; ok {
+			continue
+		}
+		visited[parent] = struct{}{}
+
+		err := manager.Projects.List().Parent(parent).Pages(ctx, func(resp *crmv3.ListProjectsResponse) error {
+			for _, p := range resp.Projects {
+				if p.State != "ACTIVE" {
+					continue
+				}
+				projects = append(projects, project{id: p.ProjectId, number: strings.TrimPrefix(p.Name, "projects/"), labels: p.Labels})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not list projects under %s", parent)
+		}
+
+		err = manager.Folders.List().Parent(parent).Pages(ctx, func(resp *crmv3.ListFoldersResponse) error {
+			for _, f := range resp.Folders {
+				if f.State != "ACTIVE" {
+					continue
+				}
+				parents = append(parents, f.Name)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not list folders under %s", parent)
+		}
+	}
+	return projects, nil
+}
+
+// matches returns true if the project passes the include/exclude filters
+func (s *projectScope) matches(p project) bool {
+	if len(s.include) > 0 && !matchesAnyPattern(p.id, s.include) {
+		return false
+	}
+	if matchesAnyPattern(p.id, s.exclude) {
+		return false
+	}
+	if len(s.includeLabels) > 0 && !matchesAnyLabel(p.labels, s.includeLabels) {
+		return false
+	}
+	if matchesAnyLabel(p.labels, s.excludeLabels) {
+		return false
+	}
+	return true
+}
+
+func matchesAnyPattern(id string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, id); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesAnyLabel matches label selectors in key or key=value form
+func matchesAnyLabel(labels map[string]string, selectors []string) bool {
+	for _, selector := range selectors {
+		key, value, hasValue := strings.Cut(selector, "=")
+		actual, ok := labels[key]
+		if !ok {
+			continue
+		}
+		if !hasValue || actual == value {
+			return true
+		}
+	}
+	return false
+}
@@ -2,7 +2,8 @@ package gcp
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"strings"
 
 	"github.com/projectdiscovery/cloudlist/pkg/schema"
 	"google.golang.org/api/compute/v1"
@@ -12,10 +13,11 @@ type cloudVMProvider struct {
 	id       string
 	compute  *compute.Service
 	projects []string
+	report   *projectErrorReport
 }
 
 func (d *cloudVMProvider) name() string {
-	return "vms"
+	return "compute"
 }
 
 // GetResource returns all the resources in the store for a provider.
@@ -24,36 +26,79 @@ func (d *cloudVMProvider) GetResource(ctx context.Context) (*schema.Resources, e
 
 	for _, project := range d.projects {
 		instances := d.compute.Instances.AggregatedList(project)
-		err := instances.Pages(context.Background(), func(ial *compute.InstanceAggregatedList) error {
+		err := instances.Pages(ctx, func(ial *compute.InstanceAggregatedList) error {
 			for _, instancesScopedList := range ial.Items {
 				for _, instance := range instancesScopedList.Instances {
-					instance := instance
-
-					if len(instance.NetworkInterfaces) == 0 {
-						continue
-					}
-					nic := instance.NetworkInterfaces[0]
-					if len(nic.AccessConfigs) == 0 {
-						continue
+					for _, nic := range instance.NetworkInterfaces {
+						list.Merge(d.networkInterfaceResources(project, instance, nic))
 					}
-					cfg := nic.AccessConfigs[0]
-
-					list.Append(&schema.Resource{
-						ID:         d.id,
-						Public:     true,
-						Provider:   providerName,
-						PublicIPv4: cfg.NatIP,
-						PublicIPv6: cfg.ExternalIpv6,
-						Service:    d.name(),
-					})
 				}
 			}
 			return nil
 		})
 		if err != nil {
-			log.Printf("Could not get all instances for project %s: %s\n", project, err)
+			d.report.record(project, d.name(), err)
 			continue
 		}
 	}
 	return list, nil
 }
+
+// networkInterfaceResources returns the internal, external and alias
+// addresses of a network interface of an instance
+func (d *cloudVMProvider) networkInterfaceResources(project string, instance *compute.Instance, nic *compute.NetworkInterface) *schema.Resources {
+	list := schema.NewResources()
+
+	aliasRanges := make([]string, 0, len(nic.AliasIpRanges))
+	for _, aliasRange := range nic.AliasIpRanges {
+		aliasRanges = append(aliasRanges, aliasRange.IpCidrRange)
+	}
+	metadata := cleanMetadata(map[string]string{
+		"project":           project,
+		"zone":              lastSegment(instance.Zone),
+		"instance_name":     instance.Name,
+		"instance_id":       fmt.Sprint(instance.Id),
+		"status":            instance.Status,
+		"network_interface": nic.Name,
+		"network":           lastSegment(nic.Network),
+		"subnetwork":        lastSegment(nic.Subnetwork),
+		"alias_ip_ranges":   strings.Join(aliasRanges, ","),
+	})
+	newResource := func() *schema.Resource {
+		return &schema.Resource{
+			ID:       d.id,
+			Provider: providerName,
+			Service:  d.name(),
+			Metadata: metadata,
+		}
+	}
+
+	internal := newResource()
+	internal.PrivateIpv4 = nic.NetworkIP
+	internal.PrivateIpv6 = nic.Ipv6Address
+	list.Append(internal)
+
+	for _, cfg := range nic.AccessConfigs {
+		external := newResource()
+		external.Public = true
+		external.PublicIPv4 = cfg.NatIP
+		external.PublicIPv6 = cfg.ExternalIpv6
+		list.Append(external)
+	}
+	for _, cfg := range nic.Ipv6AccessConfigs {
+		external := newResource()
+		external.Public = true
+		external.PublicIPv6 = cfg.ExternalIpv6
+		list.Append(external)
+	}
+
+	// single address alias ranges are secondary addresses of the instance
+	for _, aliasRange := range aliasRanges {
+		if ip, ok := strings.CutSuffix(aliasRange, "/32"); ok {
+			alias := newResource()
+			alias.PrivateIpv4 = ip
+			list.Append(alias)
+		}
+	}
+	return list
+}
@@ -34,10 +34,11 @@ func (k *K8sIngressProvider) GetResource(ctx context.Context) (*schema.Resources
 				Provider: providerName,
 				ID:       k.id,
 				DNSName:  rule.Host,
+				Service:  k.name(),
 			})
 		}
 		for _, ip := range ingress.Status.LoadBalancer.Ingress {
-			if ip.IP == "" {
+			if ip.IP != "" {
 				list.Append(&schema.Resource{
 					Public:     true,
 					Provider:   providerName,
@@ -46,7 +47,7 @@ func (k *K8sIngressProvider) GetResource(ctx context.Context) (*schema.Resources
 					Service:   k.name(),
 				})
 			}
-			if ip.Hostname == "" {
+			if ip.Hostname != "" {
 				list.Append(&schema.Resource{
 					Public:   true,
 					Provider: providerName,
@@ -100,23 +100,29 @@ func (p *Provider) Services() []string {
 
 // Resources returns the provider for an resource deployment source.
 func (p *Provider) Resources(ctx context.Context) (*schema.Resources, error) {
+	return ClusterResources(ctx, p.clientSet, p.id, p.services)
+}
+
+// ClusterResources returns the services and ingress resources of a cluster,
+// it is shared with the providers of managed kubernetes clusters.
+func ClusterResources(ctx context.Context, clientSet kubernetes.Interface, id string, services schema.ServiceMap) (*schema.Resources, error) {
 	finalList := schema.NewResources()
-	services, err := p.clientSet.CoreV1().Services("").List(ctx, metav1.ListOptions{})
-	if err != nil {
-		return nil, errorutil.NewWithErr(err).Msgf("could not list kubernetes services")
-	}
-	if p.services.Has("service") {
-		k8sServiceProvider := K8sServiceProvider{serviceClient: services, id: p.id}
+	if services.Has("service") {
+		serviceList, err := clientSet.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, errorutil.NewWithErr(err).Msgf("could not list kubernetes services")
+		}
+		k8sServiceProvider := K8sServiceProvider{serviceClient: serviceList, id: id}
 		serviceIPs, _ := k8sServiceProvider.GetResource(ctx)
 		finalList.Merge(serviceIPs)
 	}
 
-	if p.services.Has("ingress") {
-		ingress, err := p.clientSet.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
+	if services.Has("ingress") {
+		ingress, err := clientSet.NetworkingV1().Ingresses("").List(ctx, metav1.ListOptions{})
 		if err != nil {
 			return nil, errorutil.NewWithErr(err).Msgf("could not list kubernetes ingress")
 		}
-		k8sIngressProvider := K8sIngressProvider{ingress: ingress, id: p.id}
+		k8sIngressProvider := K8sIngressProvider{ingress: ingress, id: id}
 		ingressHosts, _ := k8sIngressProvider.GetResource(ctx)
 		finalList.Merge(ingressHosts)
 	}
@@ -2,6 +2,7 @@ package schema
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -63,11 +64,12 @@ func init() {
 }
 
 // appendResourceWithTypeAndMeta appends a resource with a type and metadata
-func (r *Resources) appendResourceWithTypeAndMeta(resourceType validate.ResourceType, item, id, provider, service string) {
+func (r *Resources) appendResourceWithTypeAndMeta(resourceType validate.ResourceType, item, id, provider, service string, metadata map[string]string) {
 	resource := &Resource{
 		Provider: provider,
 		ID:       id,
 		Service:  service,
+		Metadata: metadata,
 	}
 	switch resourceType {
 	case validate.DNSName:
@@ -93,31 +95,31 @@ func (r *Resources) appendResourceWithTypeAndMeta(resourceType validate.Resource
 func (r *Resources) appendResource(resource *Resource) {
 	if resource.DNSName != "" && !r.deduplicator.Contains(resource.DNSName) {
 		resourceType := validator.Identify(resource.DNSName)
-		r.appendResourceWithTypeAndMeta(resourceType, resource.DNSName, resource.ID, resource.Provider, resource.Service)
+		r.appendResourceWithTypeAndMeta(resourceType, resource.DNSName, resource.ID, resource.Provider, resource.Service, resource.Metadata)
 		r.deduplicator.Add(resource.DNSName)
 	}
 
 	if resource.PublicIPv4 != "" && !r.deduplicator.Contains(resource.PublicIPv4) {
 		resourceType := validator.Identify(resource.PublicIPv4)
-		r.appendResourceWithTypeAndMeta(resourceType, resource.PublicIPv4, resource.ID, resource.Provider, resource.Service)
+		r.appendResourceWithTypeAndMeta(resourceType, resource.PublicIPv4, resource.ID, resource.Provider, resource.Service, resource.Metadata)
 		r.deduplicator.Add(resource.PublicIPv4)
 	}
 
 	if resource.PublicIPv6 != "" && !r.deduplicator.Contains(resource.PublicIPv6) {
 		resourceType := validator.Identify(resource.PublicIPv6)
-		r.appendResourceWithTypeAndMeta(resourceType, resource.PublicIPv6, resource.ID, resource.Provider, resource.Service)
+		r.appendResourceWithTypeAndMeta(resourceType, resource.PublicIPv6, resource.ID, resource.Provider, resource.Service, resource.Metadata)
 		r.deduplicator.Add(resource.PublicIPv6)
 	}
 
 	if resource.PrivateIpv4 != "" && !r.deduplicator.Contains(resource.PrivateIpv4) {
 		resourceType := validator.Identify(resource.PrivateIpv4)
-		r.appendResourceWithTypeAndMeta(resourceType, resource.PrivateIpv4, resource.ID, resource.Provider, resource.Service)
+		r.appendResourceWithTypeAndMeta(resourceType, resource.PrivateIpv4, resource.ID, resource.Provider, resource.Service, resource.Metadata)
 		r.deduplicator.Add(resource.PrivateIpv4)
 	}
 
 	if resource.PrivateIpv6 != "" && !r.deduplicator.Contains(resource.PrivateIpv6) {
 		resourceType := validator.Identify(resource.PrivateIpv6)
-		r.appendResourceWithTypeAndMeta(resourceType, resource.PrivateIpv6, resource.ID, resource.Provider, resource.Service)
+		r.appendResourceWithTypeAndMeta(resourceType, resource.PrivateIpv6, resource.ID, resource.Provider, resource.Service, resource.Metadata)
 		r.deduplicator.Add(resource.PrivateIpv6)
 	}
 }
@@ -157,6 +159,8 @@ type Resource struct {
 	PrivateIpv6 string `json:"private_ipv6,omitempty"`
 	// DNSName is the DNS name of the resource
 	DNSName string `json:"dns_name,omitempty"`
+	// Metadata contains additional provider specific details of the resource
+	Metadata map[string]string `json:"metadata,omitempty"`
 }
 
 // ErrNoSuchKey means no such key exists in metadata.
@@ -202,8 +206,27 @@ func (ob *OptionBlock) UnmarshalYAML(unmarshal func(interface{}) error) error {
 	// Convert raw map to OptionBlock and handle special cases
 	for key, value := range rawMap {
 		switch key {
-		case "account_ids", "urls", "services":
+		case "headers":
+			if valueMap, ok := value.(map[interface{}]interface{}); ok {
+				var strArr []string
+				for k, v := range valueMap {
+					strArr = append(strArr, fmt.Sprintf("%s: %s", k, v))
+				}
+				(*ob)[key] = strings.Join(strArr, ",")
+			}
+		default:
+			// lists (account_ids, urls, services, project_ids, etc) are
+			// stored as comma separated values
 			if valueArr, ok := value.([]interface{}); ok {
+				// lists of objects (aws accounts, etc) are stored as json
+				if hasObjects(valueArr) {
+					data, err := json.Marshal(toJSONValue(valueArr))
+					if err != nil {
+						return fmt.Errorf("could not encode %s: %w", key, err)
+					}
+					(*ob)[key] = string(data)
+					continue
+				}
 				var strArr []string
 				for _, v := range valueArr {
 					switch v := v.(type) {
@@ -212,26 +235,50 @@ func (ob *OptionBlock) UnmarshalYAML(unmarshal func(interface{}) error) error {
 					case int:
 						strArr = append(strArr, fmt.Sprint(v))
 					default:
-						return fmt.Errorf("unsupported type %T in account_ids", v)
+						return fmt.Errorf("unsupported type %T in %s", v, key)
 					}
 				}
 				(*ob)[key] = strings.Join(strArr, ",")
+				continue
 			}
-		case "headers":
-			if valueMap, ok := value.(map[interface{}]interface{}); ok {
-				var strArr []string
-				for k, v := range valueMap {
-					strArr = append(strArr, fmt.Sprintf("%s: %s", k, v))
-				}
-				(*ob)[key] = strings.Join(strArr, ",")
-			}
-		default:
 			(*ob)[key] = fmt.Sprint(value)
 		}
 	}
 	return nil
 }
 
+func hasObjects(values []interface{}) bool {
+	for _, v := range values {
+		if _, ok := v.(map[interface{}]interface{}); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// toJSONValue converts yaml decoded maps to json encodable ones,
+// scalars are stored as strings like every other option value.
+func toJSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = toJSONValue(item)
+		}
+		return m
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = toJSONValue(item)
+		}
+		return items
+	case nil:
+		return nil
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 // GetMetadata returns the value for a key if it exists.
 func (o OptionBlock) GetMetadata(key string) (string, bool) {
 	data, ok := o[key]
@@ -0,0 +1,158 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	elasticsearchURL = "url"
+	index            = "index"
+	apiKey           = "api_key"
+
+	defaultIndex = "cloudlist"
+)
+
+// elasticsearchSink indexes batches of resources with the bulk api,
+// compatible with both elasticsearch and opensearch.
+type elasticsearchSink struct {
+	*batcher
+	client *retryablehttp.Client
+	url    string
+	index  string
+	apiKey string
+	auth   *httpAuth
+}
+
+func newElasticsearchSink(block schema.OptionBlock) (*elasticsearchSink, error) {
+	url, ok := block.GetMetadata(elasticsearchURL)
+	if !ok {
+		return nil, &schema.ErrNoSuchKey{Name: elasticsearchURL}
+	}
+	options, err := parseCommonOptions(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &elasticsearchSink{
+		client: newHTTPClient(options),
+		url:    strings.TrimSuffix(url, "/") + "/_bulk",
+		index:  defaultIndex,
+		auth:   parseHTTPAuth(block),
+	}
+	if value, ok := block.GetMetadata(index); ok {
+		sink.index = value
+	}
+	sink.apiKey, _ = block.GetMetadata(apiKey)
+	sink.batcher = &batcher{size: options.batchSize, retries: options.retries, flush: sink.send}
+	return sink, nil
+}
+
+// Name returns the name of the sink
+func (e *elasticsearchSink) Name() string {
+	return "elasticsearch"
+}
+
+// bulkResponse is the subset of the bulk api response used to report errors
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+func (e *elasticsearchSink) send(ctx context.Context, batch []*schema.Resource) error {
+	body := &bytes.Buffer{}
+	for _, resource := range batch {
+		// the document id is derived from the resource so that sending a
+		// batch again after a partial failure does not duplicate documents
+		action, err := jsoniter.Marshal(map[string]interface{}{"index": map[string]string{"_index": e.index, "_id": documentID(resource)}})
+		if err != nil {
+			return err
+		}
+		data, err := jsoniter.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		body.Write(action)
+		body.WriteByte('\n')
+		body.Write(data)
+		body.WriteByte('\n')
+	}
+
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, e.url, body.Bytes())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	e.auth.apply(req)
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "ApiKey "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	// the bulk api returns 200 even when some of the documents failed
+	var result bulkResponse
+	if err := jsoniter.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("could not decode bulk response: %w", err)
+	}
+	if !result.Errors {
+		return nil
+	}
+	var failed int
+	var reason string
+	var retryable bool
+	for _, item := range result.Items {
+		for _, status := range item {
+			if status.Status >= 300 {
+				failed++
+				retryable = retryable || isRetryableStatus(status.Status)
+				if reason == "" {
+					reason = status.Error.Type + ": " + status.Error.Reason
+				}
+			}
+		}
+	}
+	err = fmt.Errorf("%d of %d documents failed to index: %s", failed, len(batch), reason)
+	if !retryable {
+		return &permanentError{err: err}
+	}
+	return err
+}
+
+// documentID returns an id identifying a resource by its provider, id,
+// service and addresses
+func documentID(resource *schema.Resource) string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		resource.Provider,
+		resource.ID,
+		resource.Service,
+		resource.DNSName,
+		resource.PublicIPv4,
+		resource.PublicIPv6,
+		resource.PrivateIpv4,
+		resource.PrivateIpv6,
+	}, "\x00")))
+	return hex.EncodeToString(hash[:])
+}
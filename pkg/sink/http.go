@@ -0,0 +1,99 @@
+package sink
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const (
+	headers  = "headers"
+	token    = "token"
+	username = "username"
+	password = "password"
+)
+
+// httpAuth contains the authentication options of http based sinks
+type httpAuth struct {
+	headers  map[string]string
+	token    string
+	username string
+	password string
+}
+
+func parseHTTPAuth(block schema.OptionBlock) *httpAuth {
+	auth := &httpAuth{headers: make(map[string]string)}
+	if value, ok := block.GetMetadata(headers); ok {
+		for _, header := range strings.Split(value, ",") {
+			if parts := strings.SplitN(header, ":", 2); len(parts) == 2 {
+				key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+				if key != "" && value != "" {
+					auth.headers[key] = value
+				}
+			}
+		}
+	}
+	auth.token, _ = block.GetMetadata(token)
+	auth.username, _ = block.GetMetadata(username)
+	auth.password, _ = block.GetMetadata(password)
+	return auth
+}
+
+// apply sets the authentication headers on the request
+func (a *httpAuth) apply(req *retryablehttp.Request) {
+	for key, value := range a.headers {
+		req.Header.Set(key, value)
+	}
+	if a.token != "" {
+		req.Header.Set("Authorization", "Bearer "+a.token)
+	}
+	if a.username != "" {
+		req.SetBasicAuth(a.username, a.password)
+	}
+}
+
+// newHTTPClient returns a client retrying on network errors,
+// rate limiting and server errors.
+func newHTTPClient(options *commonOptions) *retryablehttp.Client {
+	clientOptions := retryablehttp.DefaultOptionsSingle
+	clientOptions.RetryMax = options.retries
+	clientOptions.Timeout = options.timeout
+	clientOptions.RetryWaitMin = 500 * time.Millisecond
+	clientOptions.CheckRetry = retryPolicy
+	client := retryablehttp.NewClient(clientOptions)
+	client.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	return client
+}
+
+func retryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if err != nil || ctx.Err() != nil {
+		return retryablehttp.CheckRecoverableErrors(ctx, resp, err)
+	}
+	return isRetryableStatus(resp.StatusCode), nil
+}
+
+// checkResponse returns an error for non 2xx responses, client errors
+// other than rate limiting being permanent.
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err := fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	if !isRetryableStatus(resp.StatusCode) {
+		return &permanentError{err: err}
+	}
+	return err
+}
+
+// isRetryableStatus returns true for the status codes of rate limiting and
+// server errors, which may succeed when sent again
+func isRetryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
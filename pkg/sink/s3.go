@@ -0,0 +1,126 @@
+package sink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+)
+
+const (
+	bucket       = "bucket"
+	key          = "key"
+	endpoint     = "endpoint"
+	region       = "region"
+	accessKey    = "access_key"
+	secretKey    = "secret_key"
+	sessionToken = "session_token"
+	pathStyle    = "path_style"
+
+	defaultRegion = "us-east-1"
+)
+
+// s3Sink uploads the resources of the run as a json lines object to
+// an s3 compatible bucket (aws s3, minio, etc) once the run completes.
+type s3Sink struct {
+	client *s3.Client
+	bucket string
+	key    string
+	buffer *bytes.Buffer
+}
+
+func newS3Sink(block schema.OptionBlock) (*s3Sink, error) {
+	bucketName, ok := block.GetMetadata(bucket)
+	if !ok {
+		return nil, &schema.ErrNoSuchKey{Name: bucket}
+	}
+	options, err := parseCommonOptions(block)
+	if err != nil {
+		return nil, err
+	}
+
+	loadOptions := []func(*config.LoadOptions) error{
+		config.WithRegion(defaultRegion),
+		config.WithRetryMaxAttempts(options.retries + 1),
+	}
+	if value, ok := block.GetMetadata(region); ok {
+		loadOptions = append(loadOptions, config.WithRegion(value))
+	}
+	// static keys are optional, the default credential chain is used otherwise
+	if access, ok := block.GetMetadata(accessKey); ok {
+		secret, ok := block.GetMetadata(secretKey)
+		if !ok {
+			return nil, &schema.ErrNoSuchKey{Name: secretKey}
+		}
+		token, _ := block.GetMetadata(sessionToken)
+		loadOptions = append(loadOptions, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(access, secret, token)))
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("could not load s3 config: %w", err)
+	}
+	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+		if value, ok := block.GetMetadata(endpoint); ok {
+			o.BaseEndpoint = aws.String(value)
+		}
+		if value, ok := block.GetMetadata(pathStyle); ok && strings.EqualFold(value, "true") {
+			o.UsePathStyle = true
+		}
+	})
+
+	objectKey, ok := block.GetMetadata(key)
+	if !ok {
+		objectKey = "cloudlist/{timestamp}.jsonl"
+	}
+	objectKey = strings.ReplaceAll(objectKey, "{timestamp}", time.Now().UTC().Format("20060102T150405Z"))
+
+	return &s3Sink{
+		client: client,
+		bucket: bucketName,
+		key:    objectKey,
+		buffer: &bytes.Buffer{},
+	}, nil
+}
+
+// Name returns the name of the sink
+func (s *s3Sink) Name() string {
+	return "s3"
+}
+
+func (s *s3Sink) Write(ctx context.Context, resources []*schema.Resource) error {
+	for _, resource := range resources {
+		data, err := jsoniter.Marshal(resource)
+		if err != nil {
+			return err
+		}
+		s.buffer.Write(data)
+		s.buffer.WriteByte('\n')
+	}
+	return nil
+}
+
+// Close uploads the buffered resources to the bucket, nothing is uploaded
+// when no resource was written so that the previous object is kept
+func (s *s3Sink) Close(ctx context.Context) error {
+	if s.buffer.Len() == 0 {
+		return nil
+	}
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key),
+		Body:        bytes.NewReader(s.buffer.Bytes()),
+		ContentType: aws.String("application/x-ndjson"),
+	})
+	if err != nil {
+		return fmt.Errorf("could not upload s3://%s/%s: %w", s.bucket, s.key, err)
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+package sink
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/gologger"
+)
+
+// Sink is a destination for enumerated resources in addition to the cli output.
+type Sink interface {
+	// Name returns the name of the sink
+	Name() string
+	// Write sends resources to the sink. Sinks may buffer resources
+	// until enough are available for a batch.
+	Write(ctx context.Context, resources []*schema.Resource) error
+	// Close flushes the buffered resources and releases the sink
+	Close(ctx context.Context) error
+}
+
+const (
+	sinkType  = "sink"
+	batchSize = "batch_size"
+	retries   = "retries"
+	timeout   = "timeout"
+
+	defaultBatchSize = 500
+	defaultRetries   = 3
+	defaultTimeout   = 30 * time.Second
+)
+
+// Sinks is a list of configured sinks
+type Sinks []Sink
+
+// New creates the sinks from the option blocks of a sink configuration
+func New(optionBlocks schema.Options) (Sinks, error) {
+	var sinks Sinks
+	for _, block := range optionBlocks {
+		value, ok := block.GetMetadata(sinkType)
+		if !ok {
+			continue
+		}
+		sink, err := nameToSink(value, block)
+		if err != nil {
+			return nil, fmt.Errorf("could not create sink %s: %s", value, err)
+		}
+		sinks = append(sinks, sink)
+	}
+	return sinks, nil
+}
+
+func nameToSink(value string, block schema.OptionBlock) (Sink, error) {
+	switch value {
+	case "webhook":
+		return newWebhookSink(block)
+	case "s3":
+		return newS3Sink(block)
+	case "elasticsearch", "opensearch":
+		return newElasticsearchSink(block)
+	default:
+		return nil, fmt.Errorf("invalid sink name found: %s", value)
+	}
+}
+
+// Write sends the resources to every sink, failures are reported
+// without stopping the other sinks.
+func (s Sinks) Write(ctx context.Context, resources []*schema.Resource) {
+	if len(resources) == 0 {
+		return
+	}
+	for _, sink := range s {
+		if err := sink.Write(ctx, resources); err != nil {
+			gologger.Error().Msgf("Could not write resources to sink %s: %s\n", sink.Name(), err)
+		}
+	}
+}
+
+// Close flushes and closes every sink
+func (s Sinks) Close(ctx context.Context) {
+	for _, sink := range s {
+		if err := sink.Close(ctx); err != nil {
+			gologger.Error().Msgf("Could not close sink %s: %s\n", sink.Name(), err)
+		}
+	}
+}
+
+// batcher buffers resources and flushes them in batches of a fixed size
+type batcher struct {
+	size    int
+	retries int
+	// failures is the number of attempts the first pending batch failed
+	failures int
+	pending  []*schema.Resource
+	flush    func(ctx context.Context, batch []*schema.Resource) error
+}
+
+// permanentError is returned by a flush that sending the same batch again
+// cannot fix, such as rejected credentials or an invalid index.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// sendPending flushes the first n pending resources and returns how many were
+// dropped. A batch that failed with a retryable error is kept pending until
+// it failed more than retries times, other failures drop it.
+func (b *batcher) sendPending(ctx context.Context, n int) (int, error) {
+	err := b.flush(ctx, b.pending[:n])
+	if err == nil {
+		b.pending = b.pending[n:]
+		b.failures = 0
+		return 0, nil
+	}
+	b.failures++
+	var permanent *permanentError
+	if !errors.As(err, &permanent) && b.failures <= b.retries {
+		return 0, err
+	}
+	b.pending = b.pending[n:]
+	b.failures = 0
+	return n, err
+}
+
+// Write flushes the full batches. A batch that failed with a retryable
+// error is kept pending so that the next write or Close sends it again.
+func (b *batcher) Write(ctx context.Context, resources []*schema.Resource) error {
+	b.pending = append(b.pending, resources...)
+	var errs []error
+	for len(b.pending) >= b.size {
+		dropped, err := b.sendPending(ctx, b.size)
+		if err == nil {
+			continue
+		}
+		if dropped == 0 {
+			errs = append(errs, err)
+			break
+		}
+		errs = append(errs, fmt.Errorf("%d resources were not sent: %w", dropped, err))
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes the pending resources, reporting how many could not be sent
+func (b *batcher) Close(ctx context.Context) error {
+	var lost int
+	var lastErr error
+	for len(b.pending) > 0 {
+		dropped, err := b.sendPending(ctx, min(b.size, len(b.pending)))
+		if dropped > 0 {
+			lost += dropped
+			lastErr = err
+		}
+	}
+	if lost > 0 {
+		return fmt.Errorf("%d resources were not sent: %w", lost, lastErr)
+	}
+	return nil
+}
+
+// commonOptions are the options shared by every sink
+type commonOptions struct {
+	batchSize int
+	retries   int
+	timeout   time.Duration
+}
+
+func parseCommonOptions(block schema.OptionBlock) (*commonOptions, error) {
+	options := &commonOptions{batchSize: defaultBatchSize, retries: defaultRetries, timeout: defaultTimeout}
+	if value, ok := block.GetMetadata(batchSize); ok {
+		size, err := strconv.Atoi(value)
+		if err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid %s: %s", batchSize, value)
+		}
+		options.batchSize = size
+	}
+	if value, ok := block.GetMetadata(retries); ok {
+		count, err := strconv.Atoi(value)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid %s: %s", retries, value)
+		}
+		options.retries = count
+	}
+	if value, ok := block.GetMetadata(timeout); ok {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", timeout, value)
+		}
+		options.timeout = duration
+	}
+	return options, nil
+}
@@ -0,0 +1,206 @@
+package sink
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/stretchr/testify/require"
+)
+
+func testResources(count int) []*schema.Resource {
+	var resources []*schema.Resource
+	for i := 0; i < count; i++ {
+		resources = append(resources, &schema.Resource{
+			Provider: "aws",
+			Service:  "ec2",
+			DNSName:  "host" + strings.Repeat("a", i) + ".example.com",
+		})
+	}
+	return resources
+}
+
+func TestWebhookSink(t *testing.T) {
+	var mu sync.Mutex
+	var batches [][]*schema.Resource
+	var attempts int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		// the first request fails to exercise the retry path
+		if attempts == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		require.Equal(t, "value", r.Header.Get("X-Custom"))
+
+		var batch []*schema.Resource
+		require.NoError(t, jsoniter.NewDecoder(r.Body).Decode(&batch))
+		batches = append(batches, batch)
+	}))
+	defer server.Close()
+
+	sinks, err := New(schema.Options{{
+		"sink":       "webhook",
+		"url":        server.URL,
+		"token":      "secret",
+		"headers":    "X-Custom: value",
+		"batch_size": "2",
+		"retries":    "2",
+	}})
+	require.NoError(t, err)
+	require.Len(t, sinks, 1)
+
+	sink := sinks[0]
+	require.NoError(t, sink.Write(context.Background(), testResources(3)))
+	require.NoError(t, sink.Close(context.Background()))
+
+	require.Len(t, batches, 2)
+	require.Len(t, batches[0], 2)
+	require.Len(t, batches[1], 1)
+	require.Equal(t, 3, attempts)
+}
+
+func TestWebhookSinkFailedBatches(t *testing.T) {
+	var mu sync.Mutex
+	var received int
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		var batch []*schema.Resource
+		require.NoError(t, jsoniter.NewDecoder(r.Body).Decode(&batch))
+		received += len(batch)
+	}))
+	defer server.Close()
+
+	newSink := func() Sink {
+		sink, err := newWebhookSink(schema.OptionBlock{"url": server.URL, "batch_size": "2", "retries": "1"})
+		require.NoError(t, err)
+		return sink
+	}
+
+	// a batch that failed with a server error is kept and sent again on close
+	sink := newSink()
+	require.Error(t, sink.Write(context.Background(), testResources(3)))
+	status = http.StatusOK
+	require.NoError(t, sink.Close(context.Background()))
+	require.Equal(t, 3, received)
+
+	// batches still failing after the retries are dropped and reported
+	status = http.StatusServiceUnavailable
+	sink = newSink()
+	require.Error(t, sink.Write(context.Background(), testResources(3)))
+	err := sink.Close(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "3 resources were not sent")
+
+	// a client error drops the batch at once instead of blocking the next ones
+	status = http.StatusUnauthorized
+	sink = newSink()
+	err = sink.Write(context.Background(), testResources(4))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "2 resources were not sent")
+	status = http.StatusOK
+	received = 0
+	require.NoError(t, sink.Write(context.Background(), testResources(2)))
+	require.NoError(t, sink.Close(context.Background()))
+	require.Equal(t, 2, received)
+}
+
+func TestElasticsearchSink(t *testing.T) {
+	var lines []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/_bulk", r.URL.Path)
+		require.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		username, password, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "elastic", username)
+		require.Equal(t, "changeme", password)
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			lines = append(lines, scanner.Text())
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer server.Close()
+
+	sink, err := newElasticsearchSink(schema.OptionBlock{
+		"url":      server.URL + "/",
+		"index":    "assets",
+		"username": "elastic",
+		"password": "changeme",
+	})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testResources(2)))
+	require.NoError(t, sink.Close(context.Background()))
+
+	require.Len(t, lines, 4)
+	require.JSONEq(t, `{"index":{"_index":"assets","_id":"`+documentID(testResources(1)[0])+`"}}`, lines[0])
+	require.NotEqual(t, lines[0], lines[2])
+	require.Contains(t, lines[1], "host.example.com")
+}
+
+func TestElasticsearchSinkItemErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"failed to parse"}}}]}`))
+	}))
+	defer server.Close()
+
+	sink, err := newElasticsearchSink(schema.OptionBlock{"url": server.URL})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testResources(1)))
+	err = sink.Close(context.Background())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "mapper_parsing_exception")
+}
+
+func TestS3Sink(t *testing.T) {
+	var path string
+	var body []byte
+	// stands in for an s3 compatible server such as minio using path style requests
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPut, r.Method)
+		path = r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer server.Close()
+
+	sink, err := newS3Sink(schema.OptionBlock{
+		"bucket":     "results",
+		"key":        "runs/output.jsonl",
+		"endpoint":   server.URL,
+		"path_style": "true",
+		"access_key": "minioadmin",
+		"secret_key": "minioadmin",
+	})
+	require.NoError(t, err)
+	require.NoError(t, sink.Write(context.Background(), testResources(2)))
+	require.NoError(t, sink.Close(context.Background()))
+
+	require.Equal(t, "/results/runs/output.jsonl", path)
+	require.Equal(t, 2, bytes.Count(body, []byte("\n")))
+
+	// an empty run does not replace the previous object
+	path = ""
+	empty, err := newS3Sink(schema.OptionBlock{"bucket": "results", "endpoint": server.URL, "path_style": "true", "access_key": "minioadmin", "secret_key": "minioadmin"})
+	require.NoError(t, err)
+	require.NoError(t, empty.Close(context.Background()))
+	require.Empty(t, path)
+}
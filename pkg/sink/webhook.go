@@ -0,0 +1,64 @@
+package sink
+
+import (
+	"context"
+	"net/http"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/projectdiscovery/cloudlist/pkg/schema"
+	"github.com/projectdiscovery/retryablehttp-go"
+)
+
+const webhookURL = "url"
+
+// webhookSink posts batches of resources as a json array to an url
+type webhookSink struct {
+	*batcher
+	client *retryablehttp.Client
+	url    string
+	auth   *httpAuth
+}
+
+func newWebhookSink(block schema.OptionBlock) (*webhookSink, error) {
+	url, ok := block.GetMetadata(webhookURL)
+	if !ok {
+		return nil, &schema.ErrNoSuchKey{Name: webhookURL}
+	}
+	options, err := parseCommonOptions(block)
+	if err != nil {
+		return nil, err
+	}
+
+	sink := &webhookSink{
+		client: newHTTPClient(options),
+		url:    url,
+		auth:   parseHTTPAuth(block),
+	}
+	sink.batcher = &batcher{size: options.batchSize, retries: options.retries, flush: sink.send}
+	return sink, nil
+}
+
+// Name returns the name of the sink
+func (w *webhookSink) Name() string {
+	return "webhook"
+}
+
+func (w *webhookSink) send(ctx context.Context, batch []*schema.Resource) error {
+	data, err := jsoniter.Marshal(batch)
+	if err != nil {
+		return err
+	}
+	req, err := retryablehttp.NewRequestWithContext(ctx, http.MethodPost, w.url, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	w.auth.apply(req)
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}